@@ -0,0 +1,143 @@
+// osmimport leest een OpenStreetMap extract van de grensregio en importeert
+// restaurants, tankstations en supermarkten als bedrijven.
+//
+//	go run ./cmd/osmimport -file grensregio.osm.pbf [-bbox 5.8,50.7,7.2,52.4] [-dry-run]
+//
+// Extracts zijn te maken met osmium, bijvoorbeeld:
+//
+//	osmium extract -b 5.8,50.7,7.2,52.4 germany-latest.osm.pbf -o grensregio.osm.pbf
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/osm"
+	"projectpeterperplexity/internal/services"
+)
+
+func main() {
+	file := flag.String("file", "", "OSM extract (.osm.pbf of .osm)")
+	bboxFlag := flag.String("bbox", "", "Alleen POI's binnen west,south,east,north")
+	dryRun := flag.Bool("dry-run", false, "Alleen tellen, niets opslaan")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		log.Fatal("-file is required")
+	}
+
+	var bbox *geo.BBox
+	if *bboxFlag != "" {
+		parsed, err := geo.ParseBBox(*bboxFlag)
+		if err != nil {
+			log.Fatal("Invalid -bbox: ", err)
+		}
+		bbox = &parsed
+	}
+
+	pois, err := readPOIs(*file)
+	if err != nil {
+		log.Fatal("Failed to read extract: ", err)
+	}
+
+	if bbox != nil {
+		inside := pois[:0]
+		for _, poi := range pois {
+			if bbox.Contains(geo.Point{Lat: poi.Latitude, Lng: poi.Longitude}) {
+				inside = append(inside, poi)
+			}
+		}
+		pois = inside
+	}
+	fmt.Printf("📍 %d POIs found in %s\n", len(pois), *file)
+
+	config.ConnectDatabase()
+	config.MigrateDatabase()
+
+	stats, err := services.ImportOSMPOIs(pois, *dryRun)
+	if err != nil {
+		log.Fatal("Import failed: ", err)
+	}
+
+	prefix := "✅"
+	if *dryRun {
+		prefix = "🔎 Dry-run:"
+	}
+	fmt.Printf("%s %d created, %d updated, %d unchanged, %d skipped (inactive category), %d merged\n",
+		prefix, stats.Created, stats.Updated, stats.Unchanged, stats.Skipped, stats.Merged)
+	fmt.Printf("   %d admin edits kept, %d opening_hours values not understood\n", stats.KeptEdits, stats.HoursSkipped)
+}
+
+// readPOIs leest het extract in twee rondes: eerst de POI's (nodes en ways),
+// daarna alleen de coördinaten van nodes die bij een POI-way horen.
+func readPOIs(path string) ([]*osm.POI, error) {
+	var pois []*osm.POI
+	var ways []*osm.Element
+	needed := map[int64]bool{}
+
+	err := osm.ReadFile(path, func(e *osm.Element) error {
+		if !osm.IsCandidate(e) {
+			return nil
+		}
+		if e.Type == "way" {
+			ways = append(ways, e)
+			for _, ref := range e.Refs {
+				needed[ref] = true
+			}
+			return nil
+		}
+		if poi, ok := osm.ToPOI(e); ok {
+			pois = append(pois, poi)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ways) == 0 {
+		return pois, nil
+	}
+
+	coordinates := make(map[int64]geo.Point, len(needed))
+	err = osm.ReadFile(path, func(e *osm.Element) error {
+		if e.Type == "node" && needed[e.ID] {
+			coordinates[e.ID] = geo.Point{Lat: e.Lat, Lng: e.Lon}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Way (gebouw of terrein): middelpunt van de nodes, gesloten ring niet dubbel tellen
+	for _, way := range ways {
+		refs := way.Refs
+		if len(refs) > 1 && refs[0] == refs[len(refs)-1] {
+			refs = refs[:len(refs)-1]
+		}
+
+		var lat, lng float64
+		count := 0
+		for _, ref := range refs {
+			if point, ok := coordinates[ref]; ok {
+				lat += point.Lat
+				lng += point.Lng
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+
+		way.Lat, way.Lon = lat/float64(count), lng/float64(count)
+		if poi, ok := osm.ToPOI(way); ok {
+			pois = append(pois, poi)
+		}
+	}
+
+	return pois, nil
+}
@@ -0,0 +1,60 @@
+package border
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"projectpeterperplexity/internal/geo"
+	"strconv"
+	"strings"
+)
+
+//go:embed crossings.csv
+var crossingsCSV string
+
+// Crossing - grensovergang NL-DE
+type Crossing struct {
+	Name  string    `json:"name"`
+	Road  string    `json:"road,omitempty"` // Bijv. "A1/A30" (NL/DE wegnummer)
+	Point geo.Point `json:"point"`
+}
+
+// Crossings - de meegeleverde grensovergangen (crossings.csv)
+var Crossings = mustParse(crossingsCSV)
+
+func mustParse(data string) []Crossing {
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comment = '#'
+
+	records, err := r.ReadAll()
+	if err != nil {
+		panic("border: " + err.Error())
+	}
+
+	crossings := make([]Crossing, 0, len(records))
+	for i, record := range records[1:] { // Eerste regel is de header
+		lat, errLat := strconv.ParseFloat(record[2], 64)
+		lng, errLng := strconv.ParseFloat(record[3], 64)
+		if errLat != nil || errLng != nil {
+			panic(fmt.Sprintf("border: invalid coordinate on line %d", i+2))
+		}
+		crossings = append(crossings, Crossing{Name: record[0], Road: record[1], Point: geo.Point{Lat: lat, Lng: lng}})
+	}
+
+	return crossings
+}
+
+// Nearest - dichtstbijzijnde grensovergang en de afstand in een rechte lijn
+func Nearest(p geo.Point) (Crossing, float64) {
+	var nearest Crossing
+	best := -1.0
+
+	for _, crossing := range Crossings {
+		distance := geo.DistanceKm(p, crossing.Point)
+		if best < 0 || distance < best {
+			nearest, best = crossing, distance
+		}
+	}
+
+	return nearest, best
+}
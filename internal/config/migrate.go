@@ -2,7 +2,13 @@ package config
 
 import (
 	"fmt"
+	"projectpeterperplexity/internal/geocoding"
+	"projectpeterperplexity/internal/holidays"
 	"projectpeterperplexity/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func MigrateDatabase() {
@@ -13,16 +19,238 @@ func MigrateDatabase() {
 		&models.Communication{},
 		&models.Invoice{},
 		&models.Business{},
+		&models.RoleDefinition{},
+		&models.Team{},
+		&models.APIKey{},
+		&models.UserIdentity{},
+		&models.OpeningHours{},
+		&models.OpeningHoursException{},
+		&models.BusinessTranslation{},
+		&models.CategoryTranslation{},
+		&models.Category{},
+		&models.BusinessPhoto{},
+		&models.DuplicateDismissal{},
+		&models.FuelPrice{},
+		&models.FuelReferencePrice{},
+		&models.Deal{},
+		&models.DealRedemption{},
+		&models.Review{},
+		&models.BusinessEditProposal{},
 	)
 	if err != nil {
 		panic("Failed to migrate database")
 	}
 
+	migrateSpatialIndexes()
+	migrateSearchIndexes()
+	backfillBusinessStates()
+	backfillFuelPriceConfirmations()
+	migrateCategories()
+
 	fmt.Println("✅ Database migration completed!")
 	SeedDatabase()
 }
 
+// migrateSpatialIndexes - indexen voor geo zoeken (earthdistance voor radius, point voor bbox)
+func migrateSpatialIndexes() {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS cube",
+		"CREATE EXTENSION IF NOT EXISTS earthdistance",
+		"CREATE INDEX IF NOT EXISTS idx_businesses_earth ON businesses USING gist (ll_to_earth(latitude, longitude))",
+		"CREATE INDEX IF NOT EXISTS idx_businesses_point ON businesses USING gist (point(longitude, latitude))",
+	}
+
+	for _, statement := range statements {
+		if err := DB.Exec(statement).Error; err != nil {
+			panic("Failed to create spatial indexes: " + err.Error())
+		}
+	}
+}
+
+// SeedRoles maakt ontbrekende ingebouwde rollen aan en voegt nieuwe ingebouwde
+// permissies toe aan bestaande ingebouwde rollen
+func SeedRoles() {
+	for _, role := range models.BuiltInRoles() {
+		var existing models.RoleDefinition
+		if DB.Where("name = ?", role.Name).First(&existing).Error != nil {
+			role.SeededPermissions = role.Permissions
+			DB.Create(&role)
+			fmt.Printf("✅ Built-in role %s created\n", role.Name)
+			continue
+		}
+		if !existing.BuiltIn {
+			continue
+		}
+
+		var seeded, added []string
+		for _, permission := range role.Permissions {
+			if existing.SeededPermissions.Contains(permission) {
+				continue
+			}
+			seeded = append(seeded, permission)
+			if !existing.Permissions.Contains(permission) {
+				existing.Permissions = append(existing.Permissions, permission)
+				added = append(added, permission)
+			}
+		}
+		if len(seeded) == 0 {
+			continue
+		}
+
+		existing.SeededPermissions = append(existing.SeededPermissions, seeded...)
+		DB.Model(&existing).Select("permissions", "seeded_permissions").Updates(&existing)
+		if len(added) > 0 {
+			fmt.Printf("✅ Built-in role %s: added %s\n", role.Name, strings.Join(added, ", "))
+		}
+	}
+}
+
+// SeedCategoryTranslations - standaard vertalingen, bestaande namen blijven staan
+func SeedCategoryTranslations() {
+	translations := models.DefaultCategoryTranslations()
+	DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&translations)
+}
+
+// migrateSearchIndexes - full-text (Duits + Nederlands) en trigram zoeken.
+// search_normalize maakt ü/ue, ö/oe, ä/ae en ß/ss gelijk en verwijdert accenten.
+func migrateSearchIndexes() {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS unaccent",
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		`CREATE OR REPLACE FUNCTION search_normalize(value text) RETURNS text AS $$
+			SELECT public.unaccent('public.unaccent'::regdictionary,
+				replace(replace(replace(replace(lower(coalesce(value, '')),
+					'ä', 'ae'), 'ö', 'oe'), 'ü', 'ue'), 'ß', 'ss'))
+		$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE`,
+		`CREATE OR REPLACE FUNCTION business_search_text(name text, category text, sub_category text, city text, description text) RETURNS text AS $$
+			SELECT search_normalize(concat_ws(' ', name, category, sub_category, city, description))
+		$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE`,
+		`CREATE OR REPLACE FUNCTION business_search_vector(name text, category text, sub_category text, city text, description text) RETURNS tsvector AS $$
+			SELECT setweight(to_tsvector('german', search_normalize(name)), 'A') ||
+				setweight(to_tsvector('dutch', search_normalize(name)), 'A') ||
+				setweight(to_tsvector('german', search_normalize(concat_ws(' ', category, sub_category, city))), 'B') ||
+				setweight(to_tsvector('dutch', search_normalize(concat_ws(' ', category, sub_category, city))), 'B') ||
+				setweight(to_tsvector('german', search_normalize(description)), 'C') ||
+				setweight(to_tsvector('dutch', search_normalize(description)), 'C')
+		$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE`,
+		`ALTER TABLE businesses ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (business_search_vector(name, category, sub_category, city, description)) STORED`,
+		`ALTER TABLE businesses ADD COLUMN IF NOT EXISTS search_text text
+			GENERATED ALWAYS AS (business_search_text(name, category, sub_category, city, description)) STORED`,
+		"CREATE INDEX IF NOT EXISTS idx_businesses_search_vector ON businesses USING gin (search_vector)",
+		"CREATE INDEX IF NOT EXISTS idx_businesses_search_trgm ON businesses USING gin (search_text gin_trgm_ops)",
+	}
+
+	for _, statement := range statements {
+		if err := DB.Exec(statement).Error; err != nil {
+			panic("Failed to create search indexes: " + err.Error())
+		}
+	}
+}
+
+// backfillBusinessStates - deelstaat (voor feestdagen) afleiden uit de postcode.
+// Bedrijven buiten Duitsland krijgen geen deelstaat (en dus geen Duitse feestdagen).
+func backfillBusinessStates() {
+	var businesses []models.Business
+	DB.Select("id", "country", "postal_code", "state").Find(&businesses)
+
+	for _, business := range businesses {
+		switch {
+		case business.State != "" && geocoding.CountryCode(business.Country) != "DE":
+			DB.Model(&business).UpdateColumn("state", "")
+		case business.State == "":
+			if state := holidays.StateForPostalCode(business.Country, business.PostalCode); state != "" {
+				DB.Model(&business).UpdateColumn("state", state)
+			}
+		}
+	}
+}
+
+// backfillFuelPriceConfirmations - bestaande prijzen zijn voor het laatst bevestigd
+// op het moment van meten (niet nu, anders lijken verouderde prijzen actueel)
+func backfillFuelPriceConfirmations() {
+	DB.Model(&models.FuelPrice{}).Where("last_confirmed_at IS NULL").
+		UpdateColumn("last_confirmed_at", gorm.Expr("observed_at"))
+}
+
+// migrateCategories zet de categorieboom op en zet de vrije tekst op bedrijven om
+// naar slugs. Onbekende waarden worden als nieuwe categorie aangemaakt (en gemeld),
+// zodat er niets verloren gaat; een admin kan ze daarna samenvoegen.
+func migrateCategories() {
+	var count int64
+	DB.Model(&models.Category{}).Count(&count)
+	if count == 0 {
+		for i, def := range models.DefaultCategories() {
+			parent := models.Category{Slug: def.Slug, Icon: def.Icon, SortOrder: i, IsActive: true}
+			DB.Create(&parent)
+			for j, slug := range def.Children {
+				DB.Create(&models.Category{Slug: slug, ParentID: &parent.ID, SortOrder: j, IsActive: true})
+			}
+		}
+		fmt.Println("✅ Default category tree created")
+	}
+
+	// Hoofdcategorieën
+	var categories []string
+	DB.Model(&models.Business{}).Distinct("category").Pluck("category", &categories)
+	for _, value := range categories {
+		slug := models.NormalizeCategory(value)
+		if slug == "" {
+			continue
+		}
+		ensureCategory(slug, nil)
+		if slug != value {
+			DB.Model(&models.Business{}).Where("category = ?", value).UpdateColumn("category", slug)
+		}
+	}
+
+	// Subcategorieën, per hoofdcategorie
+	type pair struct {
+		Category    string
+		SubCategory string
+	}
+	var pairs []pair
+	DB.Model(&models.Business{}).Distinct("category", "sub_category").Where("sub_category <> ''").Scan(&pairs)
+	for _, p := range pairs {
+		var parent models.Category
+		if DB.Where("slug = ? AND parent_id IS NULL", p.Category).First(&parent).Error != nil {
+			continue
+		}
+
+		slug := ensureCategory(models.NormalizeCategory(p.SubCategory), &parent)
+		if slug != p.SubCategory {
+			DB.Model(&models.Business{}).
+				Where("category = ? AND sub_category = ?", p.Category, p.SubCategory).
+				UpdateColumn("sub_category", slug)
+		}
+	}
+}
+
+// ensureCategory maakt een categorie aan als die nog niet bestaat en geeft de slug terug.
+// Bestaat de slug al onder een andere ouder, dan krijgt de subcategorie "<ouder>-<slug>".
+func ensureCategory(slug string, parent *models.Category) string {
+	var existing models.Category
+	if DB.Where("slug = ?", slug).First(&existing).Error == nil {
+		if parent == nil || (existing.ParentID != nil && *existing.ParentID == parent.ID) {
+			return slug
+		}
+		return ensureCategory(parent.Slug+"-"+slug, parent)
+	}
+
+	category := models.Category{Slug: slug, IsActive: true}
+	if parent != nil {
+		category.ParentID = &parent.ID
+	}
+	DB.Create(&category)
+	fmt.Printf("⚠️  Category %q created from existing business data, please review\n", slug)
+
+	return slug
+}
+
 func SeedDatabase() {
+	SeedRoles()
+	SeedCategoryTranslations()
+
 	// Check if users already exist
 	var userCount int64
 	DB.Model(&models.User{}).Count(&userCount)
@@ -83,6 +311,7 @@ func SeedDatabase() {
 			City:        "Düsseldorf",
 			Country:     "Germany",
 			PostalCode:  "40213",
+			State:       holidays.StateNRW,
 			Phone:       "+49 211 123456",
 			Website:     "www.rheinblick.de",
 			Email:       "info@rheinblick.de",
@@ -97,6 +326,7 @@ func SeedDatabase() {
 			City:        "Köln",
 			Country:     "Germany",
 			PostalCode:  "50667",
+			State:       holidays.StateNRW,
 			Phone:       "+49 221 987654",
 			Description: "Highway gas station with convenience store",
 			Latitude:    50.9375,
@@ -110,6 +340,7 @@ func SeedDatabase() {
 			City:        "Aachen",
 			Country:     "Germany",
 			PostalCode:  "52062",
+			State:       holidays.StateNRW,
 			Phone:       "+49 241 555123",
 			Website:     "www.santorini-aachen.de",
 			Email:       "info@santorini-aachen.de",
@@ -124,6 +355,7 @@ func SeedDatabase() {
 			City:        "Düsseldorf",
 			Country:     "Germany",
 			PostalCode:  "40212",
+			State:       holidays.StateNRW,
 			Phone:       "+49 211 456789",
 			Website:     "www.rewe.de",
 			Description: "Large supermarket with fresh products",
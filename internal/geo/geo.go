@@ -0,0 +1,109 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const earthRadiusKm = 6371.0
+
+// Point - coördinaat in graden (WGS84)
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// BBox - rechthoek in graden, zoals Leaflet's toBBoxString (west,south,east,north)
+type BBox struct {
+	West  float64 `json:"west"`
+	South float64 `json:"south"`
+	East  float64 `json:"east"`
+	North float64 `json:"north"`
+}
+
+// Valid controleert of het punt binnen het bereik van lat/lng valt
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// IsZero - 0,0 betekent in de praktijk "niet ingevuld"
+func (p Point) IsZero() bool {
+	return p.Lat == 0 && p.Lng == 0
+}
+
+// Center geeft het midden van de bbox
+func (b BBox) Center() Point {
+	return Point{Lat: (b.South + b.North) / 2, Lng: (b.West + b.East) / 2}
+}
+
+// Contains controleert of een punt in de bbox ligt
+func (b BBox) Contains(p Point) bool {
+	return p.Lat >= b.South && p.Lat <= b.North && p.Lng >= b.West && p.Lng <= b.East
+}
+
+// parseFloats leest een komma-gescheiden lijst getallen
+func parseFloats(value string, count int) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != count {
+		return nil, errors.New("expected " + strconv.Itoa(count) + " comma-separated numbers")
+	}
+
+	numbers := make([]float64, count)
+	for i, part := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, errors.New("invalid number: " + part)
+		}
+		numbers[i] = n
+	}
+
+	return numbers, nil
+}
+
+// ParsePoint leest "lat,lng"
+func ParsePoint(value string) (Point, error) {
+	numbers, err := parseFloats(value, 2)
+	if err != nil {
+		return Point{}, err
+	}
+
+	p := Point{Lat: numbers[0], Lng: numbers[1]}
+	if !p.Valid() {
+		return Point{}, errors.New("coordinate out of range")
+	}
+
+	return p, nil
+}
+
+// ParseBBox leest "west,south,east,north"
+func ParseBBox(value string) (BBox, error) {
+	numbers, err := parseFloats(value, 4)
+	if err != nil {
+		return BBox{}, err
+	}
+
+	b := BBox{West: numbers[0], South: numbers[1], East: numbers[2], North: numbers[3]}
+	if !(Point{Lat: b.South, Lng: b.West}).Valid() || !(Point{Lat: b.North, Lng: b.East}).Valid() {
+		return BBox{}, errors.New("coordinate out of range")
+	}
+	if b.West >= b.East || b.South >= b.North {
+		return BBox{}, errors.New("bbox must be west,south,east,north")
+	}
+
+	return b, nil
+}
+
+// DistanceKm - afstand in een rechte lijn (haversine)
+func DistanceKm(a, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,48 @@
+package geo
+
+// GeoJSON types (RFC 7946), alleen wat we voor de kaart nodig hebben
+
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"` // [lng, lat]
+}
+
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type FeatureCollection struct {
+	Type     string      `json:"type"`
+	Features []Feature   `json:"features"`
+	BBox     []float64   `json:"bbox,omitempty"`
+	Meta     interface{} `json:"meta,omitempty"` // Extra info (geen onderdeel van de standaard)
+}
+
+// NewPointFeature maakt een Point feature; let op de volgorde lng, lat
+func NewPointFeature(id interface{}, p Point, properties map[string]interface{}) Feature {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	return Feature{
+		Type:       "Feature",
+		ID:         id,
+		Geometry:   Geometry{Type: "Point", Coordinates: []float64{p.Lng, p.Lat}},
+		Properties: properties,
+	}
+}
+
+// NewFeatureCollection maakt een (eventueel lege) FeatureCollection
+func NewFeatureCollection(features []Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// Slice geeft de bbox in GeoJSON volgorde
+func (b BBox) Slice() []float64 {
+	return []float64{b.West, b.South, b.East, b.North}
+}
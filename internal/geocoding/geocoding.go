@@ -0,0 +1,95 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"projectpeterperplexity/internal/geo"
+	"strings"
+)
+
+// ErrNotFound - adres (postcode) onbekend in de dataset
+var ErrNotFound = errors.New("address not found")
+
+// Address - de adresvelden van een bedrijf
+type Address struct {
+	Street     string
+	PostalCode string
+	City       string
+	Country    string // Landnaam ("Germany") of ISO code ("DE")
+}
+
+// Result - gevonden locatie
+type Result struct {
+	Point      geo.Point
+	PostalCode string  // Genormaliseerd, zoals in de dataset
+	City       string  // Plaatsnaam uit de dataset
+	Country    string  // ISO code
+	RadiusKm   float64 // Spreiding van het postcodegebied (0 bij één punt)
+	Precision  string  // "postal_code"
+}
+
+// Geocoder - adres naar coördinaten. De huidige implementatie is offline
+// (postcode centroids); een live service kan later dezelfde interface krijgen.
+type Geocoder interface {
+	Geocode(ctx context.Context, address Address) (Result, error)
+}
+
+// Default - de geconfigureerde geocoder (nil als er geen dataset is)
+var Default Geocoder
+
+// Load leest de postcodebestanden uit GEOCODING_POSTCODES (komma-gescheiden).
+// Zonder bestanden blijft geocoding uit en worden coördinaten niet gecontroleerd.
+func Load() error {
+	paths := os.Getenv("GEOCODING_POSTCODES")
+	if paths == "" {
+		fmt.Println("📍 Geocoding disabled (GEOCODING_POSTCODES not set)")
+		return nil
+	}
+
+	index := NewPostcodeIndex()
+	for _, path := range strings.Split(paths, ",") {
+		if err := index.LoadFile(strings.TrimSpace(path)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	Default = index
+	fmt.Printf("📍 Geocoding: %d postal codes loaded\n", index.Len())
+	return nil
+}
+
+// countryCodes - landnamen zoals ze in de database staan
+var countryCodes = map[string]string{
+	"germany":     "DE",
+	"deutschland": "DE",
+	"duitsland":   "DE",
+	"netherlands": "NL",
+	"nederland":   "NL",
+	"niederlande": "NL",
+	"belgium":     "BE",
+	"belgië":      "BE",
+	"belgie":      "BE",
+	"belgien":     "BE",
+}
+
+// CountryCode - ISO code voor een landnaam; leeg = Duitsland (standaard in de app)
+func CountryCode(country string) string {
+	country = strings.ToLower(strings.TrimSpace(country))
+	if country == "" {
+		return "DE"
+	}
+	if code, ok := countryCodes[country]; ok {
+		return code
+	}
+	if len(country) == 2 {
+		return strings.ToUpper(country)
+	}
+	return ""
+}
+
+// NormalizePostalCode - zonder spaties, hoofdletters ("7511 ab" -> "7511AB")
+func NormalizePostalCode(postalCode string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(postalCode), ""))
+}
@@ -0,0 +1,161 @@
+package geocoding
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"projectpeterperplexity/internal/geo"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// place - één regel uit de dataset
+type place struct {
+	Point geo.Point
+	City  string
+}
+
+// PostcodeIndex - postcode centroids uit GeoNames postal code dumps
+// (download.geonames.org/export/zip: DE.zip, NL.zip). Een postcode met
+// meerdere plaatsen krijgt het gemiddelde als centroid.
+type PostcodeIndex struct {
+	places  map[string][]place
+	entries map[string]Result
+}
+
+func NewPostcodeIndex() *PostcodeIndex {
+	return &PostcodeIndex{places: map[string][]place{}, entries: map[string]Result{}}
+}
+
+// Len - aantal postcodes
+func (idx *PostcodeIndex) Len() int {
+	return len(idx.entries)
+}
+
+// LoadFile leest een GeoNames bestand (.txt of de .zip zoals gedownload)
+func (idx *PostcodeIndex) LoadFile(path string) error {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+
+		for _, file := range archive.File {
+			if !strings.EqualFold(filepath.Ext(file.Name), ".txt") || strings.EqualFold(file.Name, "readme.txt") {
+				continue
+			}
+			r, err := file.Open()
+			if err != nil {
+				return err
+			}
+			err = idx.Load(r)
+			r.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return idx.Load(f)
+}
+
+// Load leest tab-gescheiden regels: land, postcode, plaats, admin velden (6), lat, lng, nauwkeurigheid
+func (idx *PostcodeIndex) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 11 {
+			return errors.New("line " + strconv.Itoa(line) + ": expected GeoNames postal code format (tab-separated)")
+		}
+
+		lat, errLat := strconv.ParseFloat(fields[9], 64)
+		lng, errLng := strconv.ParseFloat(fields[10], 64)
+		point := geo.Point{Lat: lat, Lng: lng}
+		if errLat != nil || errLng != nil || !point.Valid() || point.IsZero() {
+			continue
+		}
+
+		key := postcodeKey(strings.ToUpper(fields[0]), NormalizePostalCode(fields[1]))
+		idx.places[key] = append(idx.places[key], place{Point: point, City: fields[2]})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	idx.rebuild()
+	return nil
+}
+
+// rebuild berekent centroid en spreiding per postcode
+func (idx *PostcodeIndex) rebuild() {
+	for key, places := range idx.places {
+		var center geo.Point
+		for _, p := range places {
+			center.Lat += p.Point.Lat
+			center.Lng += p.Point.Lng
+		}
+		center.Lat /= float64(len(places))
+		center.Lng /= float64(len(places))
+
+		var radius float64
+		for _, p := range places {
+			radius = max(radius, geo.DistanceKm(center, p.Point))
+		}
+
+		country, postalCode, _ := strings.Cut(key, ":")
+		idx.entries[key] = Result{
+			Point:      center,
+			PostalCode: postalCode,
+			City:       places[0].City,
+			Country:    country,
+			RadiusKm:   radius,
+			Precision:  "postal_code",
+		}
+	}
+}
+
+// Geocode zoekt de postcode op. Nederlandse postcodes vallen terug op de
+// vier cijfers (PC4) als de volledige postcode niet in de dataset staat.
+func (idx *PostcodeIndex) Geocode(ctx context.Context, address Address) (Result, error) {
+	country := CountryCode(address.Country)
+	postalCode := NormalizePostalCode(address.PostalCode)
+	if country == "" || postalCode == "" {
+		return Result{}, ErrNotFound
+	}
+
+	if result, ok := idx.entries[postcodeKey(country, postalCode)]; ok {
+		return result, nil
+	}
+
+	digits := strings.TrimRightFunc(postalCode, unicode.IsLetter)
+	if digits != postalCode && digits != "" {
+		if result, ok := idx.entries[postcodeKey(country, digits)]; ok {
+			return result, nil
+		}
+	}
+
+	return Result{}, ErrNotFound
+}
+
+func postcodeKey(country, postalCode string) string {
+	return country + ":" + postalCode
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyRequest struct {
+	Name      string              `json:"name" binding:"required"`
+	Scopes    []models.Permission `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time          `json:"expires_at"` // Optioneel, nil = verloopt niet
+}
+
+// GetAPIKeys - Alle API keys (zonder de sleutels zelf)
+func GetAPIKeys(c *gin.Context) {
+	var keys []models.APIKey
+
+	if err := config.DB.Preload("CreatedBy").Order("created_at DESC").Find(&keys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"count":    len(keys),
+		"api_keys": keys,
+	})
+}
+
+// CreateAPIKey - Nieuwe API key; de sleutel wordt maar één keer getoond
+func CreateAPIKey(c *gin.Context) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	var req APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	scopes, invalid := validatePermissions(req.Scopes)
+	if len(invalid) > 0 || scopes.Contains(string(models.PermAll)) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unknown or disallowed scopes",
+			"invalid": invalid,
+		})
+		return
+	}
+
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "expires_at must be in the future",
+		})
+		return
+	}
+
+	key, prefix, hash, err := services.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate API key",
+		})
+		return
+	}
+
+	apiKey := models.APIKey{
+		Name:            req.Name,
+		Prefix:          prefix,
+		KeyHash:         hash,
+		Scopes:          scopes,
+		ExpiresAt:       req.ExpiresAt,
+		CreatedByUserID: userID,
+	}
+
+	if err := config.DB.Omit("CreatedBy").Create(&apiKey).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create API key",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"api_key": apiKey,
+		"key":     key,
+		"message": "Store this key now, it cannot be shown again",
+	})
+}
+
+// RevokeAPIKey - API key intrekken (blijft bewaard voor de logs)
+func RevokeAPIKey(c *gin.Context) {
+	id, ok := paramID(c, "id", "API key")
+	if !ok {
+		return
+	}
+	var apiKey models.APIKey
+
+	if err := config.DB.First(&apiKey, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "API key not found",
+			"id":    id,
+		})
+		return
+	}
+
+	if apiKey.RevokedAt == nil {
+		now := time.Now()
+		apiKey.RevokedAt = &now
+		if err := config.DB.Model(&apiKey).Update("revoked_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to revoke API key",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"api_key": apiKey,
+		"message": "API key revoked",
+	})
+}
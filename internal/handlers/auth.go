@@ -23,6 +23,21 @@ type RegisterRequest struct {
 	Role       models.Role `json:"role"`
 	StudentID  string      `json:"student_id"`
 	University string      `json:"university"`
+	CustomerID *uint       `json:"customer_id"` // Verplicht voor eigenaren (rol owner)
+}
+
+// setAuthCookie zet het JWT als secure HTTP-only cookie
+func setAuthCookie(c *gin.Context, token string, sameSite http.SameSite) {
+	c.SetSameSite(sameSite)
+	c.SetCookie(
+		"token",                // name
+		token,                  // value
+		3600*24,                // maxAge (24 hours)
+		"/",                    // path
+		"burogrenstoerisme.nl", // domain (production)
+		true,                   // secure (HTTPS only)
+		true,                   // httpOnly (not accessible via JS)
+	)
 }
 
 func Login(c *gin.Context) {
@@ -52,17 +67,7 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	// Set secure HTTP-only cookie
-	c.SetSameSite(http.SameSiteStrictMode)
-	c.SetCookie(
-		"token",                // name
-		token,                  // value
-		3600*24,                // maxAge (24 hours)
-		"/",                    // path
-		"burogrenstoerisme.nl", // domain (production)
-		true,                   // secure (HTTPS only)
-		true,                   // httpOnly (not accessible via JS)
-	)
+	setAuthCookie(c, token, http.SameSiteStrictMode)
 
 	// Also return in response (for compatibility)
 	c.JSON(200, gin.H{
@@ -107,6 +112,7 @@ func Register(c *gin.Context) {
 		Role:       req.Role,
 		StudentID:  req.StudentID,
 		University: req.University,
+		CustomerID: req.CustomerID,
 		IsActive:   true,
 	}
 
@@ -123,6 +129,30 @@ func Register(c *gin.Context) {
 		user.Role = models.RoleStudent
 	}
 
+	// Role must exist in the database
+	if _, err := services.GetRoleDefinition(user.Role); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unknown role",
+			"role":  user.Role,
+		})
+		return
+	}
+
+	// Eigenaren horen bij een klant, andere rollen niet
+	if user.Role == models.RoleOwner {
+		if user.CustomerID == nil || config.DB.First(&models.Customer{}, *user.CustomerID).Error != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Owner accounts need an existing customer_id",
+			})
+			return
+		}
+	} else if user.CustomerID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "customer_id is only allowed for owner accounts",
+		})
+		return
+	}
+
 	// Save user
 	result = config.DB.Create(&user)
 	if result.Error != nil {
@@ -137,18 +167,19 @@ func Register(c *gin.Context) {
 		"success": true,
 		"message": "User created successfully",
 		"user": gin.H{
-			"id":         user.ID,
-			"email":      user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-			"role":       user.Role,
+			"id":          user.ID,
+			"email":       user.Email,
+			"first_name":  user.FirstName,
+			"last_name":   user.LastName,
+			"role":        user.Role,
+			"customer_id": user.CustomerID,
 		},
 	})
 }
 
 // GetProfile handler
 func GetProfile(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not authenticated",
@@ -166,16 +197,24 @@ func GetProfile(c *gin.Context) {
 		return
 	}
 
+	// Permissions so the frontend can show/hide features
+	permissions := models.StringList{}
+	if definition, err := services.GetRoleDefinition(user.Role); err == nil {
+		permissions = definition.Permissions
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"user": gin.H{
-			"id":         user.ID,
-			"email":      user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-			"role":       user.Role,
-			"student_id": user.StudentID,
-			"university": user.University,
+			"id":          user.ID,
+			"email":       user.Email,
+			"first_name":  user.FirstName,
+			"last_name":   user.LastName,
+			"role":        user.Role,
+			"permissions": permissions,
+			"student_id":  user.StudentID,
+			"university":  user.University,
+			"customer_id": user.CustomerID,
 		},
 	})
 }
@@ -1,35 +1,60 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/holidays"
 	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// GetBusinesses - Support voor filtering
+// GetBusinesses - Support voor zoeken (?q=), filtering, geo zoeken, paginering (cursor), sortering en ?fields=
 func GetBusinesses(c *gin.Context) {
-	var businesses []models.Business
+	businesses := []models.Business{}
 
-	// Query parameters
-	category := c.Query("category")       // ?category=restaurant
-	city := c.Query("city")               // ?city=Düsseldorf
-	subcategory := c.Query("subcategory") // ?subcategory=grieks
+	filters, err := parseBusinessFilters(c)
+	if err != nil {
+		writeFilterError(c, err)
+		return
+	}
 
-	query := config.DB.Where("is_active = ?", true)
+	page, err := parseBusinessPage(c, filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid pagination",
+			"details": err.Error(),
+		})
+		return
+	}
 
 	// Apply filters
-	if category != "" {
-		query = query.Where("category = ?", category)
-	}
+	base := filters.Apply(config.DB.Model(&models.Business{}).Where("businesses.is_active = ?", true)).
+		Session(&gorm.Session{})
 
-	if city != "" {
-		query = query.Where("city ILIKE ?", "%"+city+"%")
+	// Totaal voor alle pagina's samen
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
 	}
 
-	if subcategory != "" {
-		query = query.Where("sub_category = ?", subcategory)
+	query, err := page.Apply(filters.WithComputed(base), filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid pagination",
+			"details": err.Error(),
+		})
+		return
 	}
 
 	// Execute query
@@ -43,41 +68,316 @@ func GetBusinesses(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	nextCursor := ""
+	if len(businesses) > page.Limit {
+		businesses = businesses[:page.Limit]
+		last := &businesses[len(businesses)-1]
+		nextCursor = page.NextCursor(last)
+	}
+
+	if !localizeBusinesses(c, businesses) {
+		return
+	}
+
+	if err := services.AttachThumbnails(businesses); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if filters.From != nil {
+		services.AttachTravel(filters.From.Point, businesses)
+	}
+
+	var items interface{} = businesses
+	if len(page.Fields) > 0 {
+		items, err = projectFields(businesses, page.Fields)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to select fields",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	response := gin.H{
 		"success":    true,
 		"count":      len(businesses),
-		"businesses": businesses,
-		"filters": gin.H{
-			"category":    category,
-			"city":        city,
-			"subcategory": subcategory,
-		},
-	})
+		"total":      total,
+		"businesses": items,
+		"filters":    filters.Response(),
+		"pagination": page.Response(total, nextCursor),
+	}
+
+	// ?facets=true: aantallen voor de filteropties in dezelfde response
+	if c.Query("facets") == "true" {
+		facets, err := countBusinessFacets(filters, requestLanguage(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"details": err.Error(),
+			})
+			return
+		}
+		response["facets"] = facets
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetBusinessByID - Specifiek bedrijf ophalen
 func GetBusinessByID(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := paramID(c, "id", "business")
+	if !ok {
+		return
+	}
 	var business models.Business
 
-	result := config.DB.First(&business, id)
+	// Inactieve vermeldingen zijn niet publiek zichtbaar
+	result := services.WithListingTier(config.DB).
+		Preload("OpeningHours", func(db *gorm.DB) *gorm.DB { return db.Order("weekday, opens") }).
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("kind, sort_order, id") }).
+		Preload("HoursExceptions", "date >= ?", time.Now().In(services.BerlinLocation).AddDate(0, 0, -1).Format("2006-01-02")).
+		Preload("Deals", services.PreloadCurrentDeals(time.Now())).
+		Where("businesses.is_active = ?", true).
+		First(&business, id)
 
 	if result.Error != nil {
+		// Samengevoegd bedrijf: doorverwijzen naar het overgebleven bedrijf
+		var merged models.Business
+		if config.DB.Select("id", "merged_into_id").Where("merged_into_id IS NOT NULL").First(&merged, id).Error == nil {
+			c.Redirect(http.StatusMovedPermanently, "/api/businesses/"+strconv.FormatUint(uint64(*merged.MergedIntoID), 10))
+			return
+		}
+
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Business not found",
+			"id":    id,
+		})
+		return
+	}
+
+	services.FillPhotoURLs(business.Photos)
+
+	// Deals alleen zolang de klant actief is
+	if len(business.Deals) > 0 {
+		active, err := services.BusinessHasActiveCustomer(&business)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"details": err.Error(),
+			})
+			return
+		}
+		if !active {
+			business.Deals = nil
+		}
+		services.HideDealCodes(business.Deals)
+	}
+
+	openNow := services.IsOpenAt(&business, business.OpeningHours, business.HoursExceptions, time.Now())
+	business.OpenNow = &openNow
+
+	// Actuele brandstofprijzen voor tankstations
+	if business.FuelStationID != nil {
+		prices, err := services.LatestFuelPrices(c.Request.Context(), []uint{business.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"details": err.Error(),
+			})
+			return
+		}
+		business.FuelPrices = prices
+	}
+
+	localized := []models.Business{business}
+	if !localizeBusinesses(c, localized) {
+		return
+	}
+
+	c.JSON(http.StatusOK, localized[0])
+}
+
+// BusinessRequest - velden die via de admin API gezet mogen worden
+type BusinessRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Category    string  `json:"category" binding:"required"`
+	SubCategory string  `json:"sub_category"`
+	Address     string  `json:"address"`
+	City        string  `json:"city"`
+	Country     string  `json:"country"`
+	PostalCode  string  `json:"postal_code"`
+	State       string  `json:"state" binding:"omitempty,len=2"` // Leeg: afgeleid uit de postcode
+	Latitude    float64 `json:"latitude" binding:"min=-90,max=90"`
+	Longitude   float64 `json:"longitude" binding:"min=-180,max=180"`
+	Phone       string  `json:"phone"`
+	Website     string  `json:"website"`
+	Email       string  `json:"email" binding:"omitempty,email"`
+	Description string  `json:"description"`
+	CustomerID  *uint   `json:"customer_id"`
+
+	FuelStationID *string `json:"fuel_station_id"` // Tankerkönig station ID (tankstations)
+}
+
+// requestFromBusiness vult een request met de huidige waarden (basis voor PATCH)
+func requestFromBusiness(business *models.Business) BusinessRequest {
+	return BusinessRequest{
+		Name:        business.Name,
+		Category:    business.Category,
+		SubCategory: business.SubCategory,
+		Address:     business.Address,
+		City:        business.City,
+		Country:     business.Country,
+		PostalCode:  business.PostalCode,
+		State:       business.State,
+		Latitude:    business.Latitude,
+		Longitude:   business.Longitude,
+		Phone:       business.Phone,
+		Website:     business.Website,
+		Email:       business.Email,
+		Description: business.Description,
+		CustomerID:  business.CustomerID,
+
+		FuelStationID: business.FuelStationID,
+	}
+}
+
+// apply zet de request velden op het bedrijf
+func (req *BusinessRequest) apply(business *models.Business) {
+	business.Name = strings.TrimSpace(req.Name)
+	business.Category = strings.TrimSpace(req.Category)
+	business.SubCategory = strings.TrimSpace(req.SubCategory)
+	business.Address = req.Address
+	business.City = req.City
+	business.Country = req.Country
+	business.PostalCode = req.PostalCode
+	business.State = strings.ToUpper(req.State)
+	business.Latitude = req.Latitude
+	business.Longitude = req.Longitude
+	business.Phone = req.Phone
+	business.Website = req.Website
+	business.Email = req.Email
+	business.Description = req.Description
+	business.CustomerID = req.CustomerID
+	business.FuelStationID = req.FuelStationID
+	if business.FuelStationID != nil && strings.TrimSpace(*business.FuelStationID) == "" {
+		business.FuelStationID = nil
+	}
+
+	// Set defaults
+	if business.Country == "" {
+		business.Country = "Germany"
+	}
+	if business.State == "" {
+		business.State = holidays.StateForPostalCode(business.Country, business.PostalCode)
+	}
+}
+
+// validateCustomer controleert of de gekoppelde klant bestaat
+func validateCustomer(c *gin.Context, customerID *uint) bool {
+	if customerID == nil {
+		return true
+	}
+
+	var customer models.Customer
+	if err := config.DB.First(&customer, *customerID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Customer not found",
+			"id":    *customerID,
+		})
+		return false
+	}
+
+	return true
+}
+
+// geocodeBusiness vult of controleert de coördinaten aan de hand van de postcode
+func geocodeBusiness(c *gin.Context, business *models.Business) bool {
+	if err := services.GeocodeBusiness(c.Request.Context(), business); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Geocoding failed",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// addLocationWarning - waarschuwing in de response als de coördinaten niet bij de postcode passen
+func addLocationWarning(response gin.H, business *models.Business) {
+	switch business.GeocodeStatus {
+	case models.GeocodeMismatch:
+		response["warning"] = fmt.Sprintf("Coordinates are %.1f km from postal code %s; send latitude/longitude 0 to use the postal code centre",
+			*business.GeocodeDistanceKm, business.PostalCode)
+	case models.GeocodeUnknownPostcode:
+		response["warning"] = "Postal code " + business.PostalCode + " not found; coordinates were not checked"
+	}
+}
+
+// findBusiness haalt een bedrijf op (ook inactieve) voor admin endpoints
+func findBusiness(c *gin.Context) (*models.Business, bool) {
+	id, ok := paramID(c, "id", "business")
+	if !ok {
+		return nil, false
+	}
+	var business models.Business
+
+	if err := config.DB.First(&business, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Business not found",
 			"id":    id,
 		})
+		return nil, false
+	}
+
+	return &business, true
+}
+
+// GetAdminBusinesses - Alle bedrijven inclusief inactieve (?status=active|inactive|merged, ?geocode_status=)
+func GetAdminBusinesses(c *gin.Context) {
+	var businesses []models.Business
+	query := config.DB.Order("name")
+
+	switch c.Query("status") {
+	case "active":
+		query = query.Where("is_active = ?", true)
+	case "inactive":
+		query = query.Where("is_active = ? AND merged_into_id IS NULL", false)
+	case "merged":
+		query = query.Where("merged_into_id IS NOT NULL")
+	default:
+		query = query.Where("merged_into_id IS NULL")
+	}
+
+	// ?geocode_status=mismatch - bedrijven waarvan de coördinaten niet bij de postcode passen
+	if status := c.Query("geocode_status"); status != "" {
+		query = query.Where("geocode_status = ?", status)
+	}
+
+	if err := query.Find(&businesses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
 		return
 	}
 
-	c.JSON(http.StatusOK, business)
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"count":      len(businesses),
+		"businesses": businesses,
+	})
 }
 
 // CreateBusiness - Nieuw bedrijf toevoegen
 func CreateBusiness(c *gin.Context) {
-	var business models.Business
+	var req BusinessRequest
 
-	if err := c.ShouldBindJSON(&business); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid JSON data",
 			"details": err.Error(),
@@ -85,13 +385,19 @@ func CreateBusiness(c *gin.Context) {
 		return
 	}
 
-	// Set defaults
-	if business.Country == "" {
-		business.Country = "Germany"
+	if !validateCustomer(c, req.CustomerID) || !validateBusinessCategory(c, &req) {
+		return
 	}
+
+	var business models.Business
+	req.apply(&business)
 	business.IsActive = true
 
-	result := config.DB.Create(&business)
+	if !geocodeBusiness(c, &business) {
+		return
+	}
+
+	result := config.DB.Omit("Customer").Create(&business)
 
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -101,9 +407,160 @@ func CreateBusiness(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"success":  true,
 		"business": business,
 		"message":  "Business created successfully",
+	}
+	addLocationWarning(response, &business)
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateBusiness - Bedrijf wijzigen (PUT: alle velden, PATCH: alleen meegestuurde velden)
+func UpdateBusiness(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	var req BusinessRequest
+	if c.Request.Method == http.MethodPatch {
+		req = requestFromBusiness(business)
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !validateCustomer(c, req.CustomerID) || !validateBusinessCategory(c, &req) {
+		return
+	}
+
+	// Nieuwe postcode of land zonder nieuwe deelstaat: opnieuw afleiden
+	if (req.PostalCode != business.PostalCode || req.Country != business.Country) && req.State == business.State {
+		req.State = ""
+	}
+
+	req.apply(business)
+
+	if !geocodeBusiness(c, business) {
+		return
+	}
+
+	if err := config.DB.Omit("Customer").Save(business).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update business",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := gin.H{
+		"success":  true,
+		"business": business,
+		"message":  "Business updated successfully",
+	}
+	addLocationWarning(response, business)
+	c.JSON(http.StatusOK, response)
+}
+
+// setBusinessActive - Bedrijf (de)activeren; inactieve bedrijven zijn niet publiek zichtbaar
+func setBusinessActive(c *gin.Context, active bool) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Model(business).Update("is_active", active).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update business",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	message := "Business deactivated"
+	if active {
+		message = "Business activated"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"business": business,
+		"message":  message,
+	})
+}
+
+// DeactivateBusiness - Bedrijf verbergen zonder te verwijderen
+func DeactivateBusiness(c *gin.Context) {
+	setBusinessActive(c, false)
+}
+
+// ActivateBusiness - Gedeactiveerd bedrijf weer zichtbaar maken
+func ActivateBusiness(c *gin.Context) {
+	setBusinessActive(c, true)
+}
+
+// DeleteBusiness - Bedrijf definitief verwijderen (alleen admin)
+func DeleteBusiness(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	var photos []models.BusinessPhoto
+	config.DB.Where("business_id = ?", business.ID).Find(&photos)
+
+	// Openingstijden, uitzonderingen, vertalingen, foto's, prijzen en deals gaan mee
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.BusinessPhoto{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.OpeningHours{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.OpeningHoursException{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.BusinessTranslation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.FuelPrice{}).Error; err != nil {
+			return err
+		}
+		deals := tx.Model(&models.Deal{}).Select("id").Where("business_id = ?", business.ID)
+		if err := tx.Where("deal_id IN (?)", deals).Delete(&models.DealRedemption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.Deal{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.Review{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.BusinessEditProposal{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(business).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete business",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	for i := range photos {
+		services.DeletePhotoFiles(c.Request.Context(), &photos[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Business deleted successfully",
 	})
 }
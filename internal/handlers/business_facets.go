@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FacetCount - aantal actieve bedrijven per waarde
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"` // Vertaalde naam (categorieën)
+	Count int64  `json:"count"`
+}
+
+// businessFacet - één facet: kolom plus hoe het eigen filter weggelaten wordt
+type businessFacet struct {
+	Name       string
+	Column     string
+	Translated bool // Waarden zijn categorie slugs met vertalingen
+	Clear      func(f *businessFilters)
+}
+
+var businessFacetDefinitions = []businessFacet{
+	{Name: "category", Column: "businesses.category", Translated: true, Clear: func(f *businessFilters) { f.Category = "" }},
+	{Name: "subcategory", Column: "businesses.sub_category", Translated: true, Clear: func(f *businessFilters) { f.SubCategory = "" }},
+	{Name: "city", Column: "businesses.city", Clear: func(f *businessFilters) { f.City = "" }},
+}
+
+// countBusinessFacets telt per facet met alle andere filters actief, zodat de UI
+// binnen de huidige selectie kan doorklikken (drill-down)
+func countBusinessFacets(filters businessFilters, lang string) (map[string][]FacetCount, error) {
+	facets := make(map[string][]FacetCount, len(businessFacetDefinitions))
+
+	for _, facet := range businessFacetDefinitions {
+		facetFilters := filters
+		facet.Clear(&facetFilters)
+
+		counts := []FacetCount{}
+		err := facetFilters.Apply(config.DB.Model(&models.Business{}).Where("businesses.is_active = ?", true)).
+			Select(facet.Column + " AS value, COUNT(*) AS count").
+			Where(facet.Column + " <> ''").
+			Group(facet.Column).
+			Order("count DESC, value").
+			Scan(&counts).Error
+		if err != nil {
+			return nil, err
+		}
+
+		if facet.Translated {
+			if err := labelFacetCounts(counts, lang); err != nil {
+				return nil, err
+			}
+		}
+
+		facets[facet.Name] = counts
+	}
+
+	return facets, nil
+}
+
+// labelFacetCounts zet de vertaalde categorienaam bij elke waarde
+func labelFacetCounts(counts []FacetCount, lang string) error {
+	slugs := make([]string, len(counts))
+	for i, count := range counts {
+		slugs[i] = count.Value
+	}
+
+	names, err := services.CategoryNames(slugs, lang)
+	if err != nil {
+		return err
+	}
+
+	for i := range counts {
+		counts[i].Label = names[counts[i].Value]
+	}
+	return nil
+}
+
+// GetBusinessFacets - Aantallen per categorie, subcategorie en stad voor de huidige filters
+func GetBusinessFacets(c *gin.Context) {
+	filters, err := parseBusinessFilters(c)
+	if err != nil {
+		writeFilterError(c, err)
+		return
+	}
+
+	facets, err := countBusinessFacets(filters, requestLanguage(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"facets":  facets,
+		"filters": filters.Response(),
+	})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseOpenAt leest ?open_now=true of ?open_at= (RFC3339, of lokale tijd "2006-01-02T15:04" in Europe/Berlin)
+func parseOpenAt(c *gin.Context) (*time.Time, error) {
+	if value := c.Query("open_at"); value != "" {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return &t, nil
+		}
+		t, err := time.ParseInLocation("2006-01-02T15:04", value, services.BerlinLocation)
+		if err != nil {
+			return nil, errors.New("open_at must be RFC3339 or YYYY-MM-DDTHH:MM")
+		}
+		return &t, nil
+	}
+
+	if c.Query("open_now") == "true" {
+		now := time.Now()
+		return &now, nil
+	}
+
+	return nil, nil
+}
+
+// holidaySQL - is de datum een feestdag in de deelstaat van het bedrijf
+func holidaySQL(date time.Time) (string, []interface{}) {
+	nationwide, states := services.HolidayStates(date)
+	switch {
+	case nationwide:
+		return "TRUE", nil
+	case len(states) > 0:
+		return "businesses.state IN ?", []interface{}{states}
+	default:
+		return "FALSE", nil
+	}
+}
+
+// weekdaySQL - weekdag voor de openingstijden (feestdag = models.HolidayWeekday)
+func weekdaySQL(date time.Time) (string, []interface{}) {
+	holiday, args := holidaySQL(date)
+	return fmt.Sprintf("(CASE WHEN %s THEN %d ELSE %d END)", holiday, models.HolidayWeekday, int(date.Weekday())), args
+}
+
+// openAtCondition - SQL variant van services.IsOpenAt: uitzonderingen op de datum
+// gaan voor de weekplanning, en uren tot na middernacht tellen ook voor de volgende dag
+func openAtCondition(t time.Time) (string, []interface{}) {
+	moment := services.NewOpenMoment(t)
+
+	const exceptionExists = "EXISTS (SELECT 1 FROM opening_hours_exceptions e WHERE e.business_id = businesses.id AND e.date = ?)"
+
+	todayWeekday, todayArgs := weekdaySQL(moment.Date)
+	prevWeekday, prevArgs := weekdaySQL(moment.PrevDate)
+
+	today := fmt.Sprintf(`(CASE WHEN %s
+		THEN EXISTS (SELECT 1 FROM opening_hours_exceptions e WHERE e.business_id = businesses.id AND e.date = ?
+			AND NOT e.closed AND e.opens <= ? AND (e.closes > ? OR e.closes <= e.opens))
+		ELSE EXISTS (SELECT 1 FROM opening_hours h WHERE h.business_id = businesses.id AND h.weekday = %s
+			AND h.opens <= ? AND (h.closes > ? OR h.closes <= h.opens))
+		END)`, exceptionExists, todayWeekday)
+
+	previous := fmt.Sprintf(`(CASE WHEN %s
+		THEN EXISTS (SELECT 1 FROM opening_hours_exceptions e WHERE e.business_id = businesses.id AND e.date = ?
+			AND NOT e.closed AND e.closes <= e.opens AND ? < e.closes)
+		ELSE EXISTS (SELECT 1 FROM opening_hours h WHERE h.business_id = businesses.id AND h.weekday = %s
+			AND h.closes <= h.opens AND ? < h.closes)
+		END)`, exceptionExists, prevWeekday)
+
+	args := []interface{}{moment.Date, moment.Date, moment.Time, moment.Time}
+	args = append(args, todayArgs...)
+	args = append(args, moment.Time, moment.Time)
+	args = append(args, moment.PrevDate, moment.PrevDate, moment.Time)
+	args = append(args, prevArgs...)
+	args = append(args, moment.Time)
+
+	return "(" + today + " OR " + previous + ")", args
+}
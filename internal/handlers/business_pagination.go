@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// sortExpressions - toegestane waarden voor ?sort= (met - ervoor: aflopend)
+var sortExpressions = map[string]string{
+	"name":       "businesses.name",
+	"city":       "COALESCE(businesses.city, '')",
+	"created_at": "businesses.created_at",
+	"distance":   earthDistanceSQL,
+	"relevance":  searchRankSQL,
+}
+
+// pageCursor - positie van het laatste resultaat (niveau, sorteerwaarde + id)
+type pageCursor struct {
+	Tier  *int            `json:"t,omitempty"` // Alleen bij de standaard sortering
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+// businessPage - paginering en sortering van GET /api/businesses
+type businessPage struct {
+	Limit  int
+	Sort   string // name, city, created_at, distance
+	Desc   bool
+	Cursor *pageCursor
+	Fields []string // leeg = alle velden
+	Tiered bool     // Zonder ?sort=: premium eerst
+}
+
+// parseBusinessPage leest limit, sort, cursor en fields
+func parseBusinessPage(c *gin.Context, filters businessFilters) (businessPage, error) {
+	page := businessPage{Limit: defaultPageLimit, Sort: "name", Tiered: true}
+	if filters.Origin() != nil {
+		page.Sort = "distance"
+	}
+	if !filters.Search.IsEmpty() {
+		page.Sort = "relevance"
+		page.Desc = true
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > maxPageLimit {
+			return page, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		page.Limit = value
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		page.Tiered = false
+		page.Desc = strings.HasPrefix(sort, "-")
+		page.Sort = strings.TrimPrefix(sort, "-")
+
+		if _, ok := sortExpressions[page.Sort]; !ok {
+			return page, errors.New("sort must be one of name, city, created_at, distance, relevance")
+		}
+		if page.Sort == "distance" && filters.Origin() == nil {
+			return page, errors.New("sort=distance requires near, from or bbox")
+		}
+		if page.Sort == "relevance" && filters.Search.IsEmpty() {
+			return page, errors.New("sort=relevance requires q")
+		}
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		data, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return page, errors.New("invalid cursor")
+		}
+		page.Cursor = &pageCursor{}
+		if err := json.Unmarshal(data, page.Cursor); err != nil || page.Cursor.ID == 0 || (page.Cursor.Tier != nil) != page.Tiered {
+			return page, errors.New("invalid cursor")
+		}
+	}
+
+	if fields := c.Query("fields"); fields != "" {
+		allowed := businessJSONFields()
+		page.Fields = []string{"id"}
+		for _, field := range strings.Split(fields, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" || field == "id" {
+				continue
+			}
+			if !allowed[field] {
+				return page, fmt.Errorf("unknown field %q", field)
+			}
+			page.Fields = append(page.Fields, field)
+		}
+	}
+
+	return page, nil
+}
+
+// sortExpression geeft de SQL expressie (met argumenten) voor de sortering
+func (p businessPage) sortExpression(filters businessFilters) (string, []interface{}) {
+	switch p.Sort {
+	case "distance":
+		origin := filters.Origin()
+		return earthDistanceSQL, []interface{}{origin.Lat, origin.Lng}
+	case "relevance":
+		return filters.searchRank()
+	}
+	return sortExpressions[p.Sort], nil
+}
+
+// cursorValue zet de JSON waarde uit de cursor om naar het juiste type
+func (p businessPage) cursorValue() (interface{}, error) {
+	switch p.Sort {
+	case "distance", "relevance":
+		var value float64
+		err := json.Unmarshal(p.Cursor.Value, &value)
+		return value, err
+	case "created_at":
+		var value time.Time
+		err := json.Unmarshal(p.Cursor.Value, &value)
+		return value, err
+	default:
+		var value string
+		err := json.Unmarshal(p.Cursor.Value, &value)
+		return value, err
+	}
+}
+
+// tierKey - sorteerexpressie voor het niveau; premium staat in beide richtingen vooraan
+func (p businessPage) tierKey() string {
+	if p.Desc {
+		return services.ListingTierBoostSQL
+	}
+	return "-" + services.ListingTierBoostSQL
+}
+
+// tierValue - waarde van tierKey voor een bedrijf
+func (p businessPage) tierValue(business *models.Business) int {
+	rank := models.ListingTierBoost(business.ListingTier)
+	if p.Desc {
+		return rank
+	}
+	return -rank
+}
+
+// Apply voegt keyset paginering en een stabiele sortering ([niveau,] sorteerwaarde, id) toe
+func (p businessPage) Apply(query *gorm.DB, filters businessFilters) (*gorm.DB, error) {
+	expression, args := p.sortExpression(filters)
+
+	direction, operator := "ASC", ">"
+	if p.Desc {
+		direction, operator = "DESC", "<"
+	}
+
+	if p.Cursor != nil {
+		value, err := p.cursorValue()
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		whereArgs := append([]interface{}{}, args...)
+		if p.Tiered {
+			whereArgs = append(whereArgs, *p.Cursor.Tier, value, p.Cursor.ID)
+			query = query.Where(fmt.Sprintf("(%s, %s, businesses.id) %s (?, ?, ?)", p.tierKey(), expression, operator), whereArgs...)
+		} else {
+			whereArgs = append(whereArgs, value, p.Cursor.ID)
+			query = query.Where(fmt.Sprintf("(%s, businesses.id) %s (?, ?)", expression, operator), whereArgs...)
+		}
+	}
+
+	orderSQL := fmt.Sprintf("%s %s, businesses.id %s", expression, direction, direction)
+	if p.Tiered {
+		orderSQL = fmt.Sprintf("%s %s, %s", p.tierKey(), direction, orderSQL)
+	}
+	order := clause.OrderBy{Expression: clause.Expr{
+		SQL:                orderSQL,
+		Vars:               args,
+		WithoutParentheses: true,
+	}}
+
+	// Eén extra rij ophalen om te weten of er een volgende pagina is
+	return query.Order(order).Limit(p.Limit + 1), nil
+}
+
+// NextCursor maakt de cursor voor de pagina na dit bedrijf (het laatste resultaat)
+func (p businessPage) NextCursor(last *models.Business) string {
+	data, err := json.Marshal(p.sortValue(last))
+	if err != nil {
+		return ""
+	}
+	next := pageCursor{Value: data, ID: last.ID}
+	if p.Tiered {
+		tier := p.tierValue(last)
+		next.Tier = &tier
+	}
+	cursor, err := json.Marshal(next)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(cursor)
+}
+
+// Response - paginering terug in de response
+func (p businessPage) Response(total int64, nextCursor string) gin.H {
+	sort := p.Sort
+	if p.Desc {
+		sort = "-" + sort
+	}
+	response := gin.H{
+		"limit":       p.Limit,
+		"sort":        sort,
+		"tiered":      p.Tiered,
+		"total":       total,
+		"next_cursor": nil,
+		"has_more":    nextCursor != "",
+	}
+	if nextCursor != "" {
+		response["next_cursor"] = nextCursor
+	}
+	return response
+}
+
+// projectFields beperkt de JSON van elk item tot de gevraagde velden
+func projectFields(items interface{}, fields []string) ([]map[string]interface{}, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var full []map[string]interface{}
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, err
+	}
+
+	projected := make([]map[string]interface{}, len(full))
+	for i, item := range full {
+		projected[i] = make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if value, ok := item[field]; ok {
+				projected[i][field] = value
+			}
+		}
+	}
+
+	return projected, nil
+}
+
+// jsonFieldNames - JSON veldnamen van een struct (voor ?fields= validatie)
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			names[tag] = true
+		}
+	}
+	return names
+}
+
+// businessJSONFields - velden die via ?fields= gekozen kunnen worden
+func businessJSONFields() map[string]bool {
+	return jsonFieldNames(reflect.TypeOf(models.Business{}))
+}
+
+// sortValue - sorteerwaarde van een bedrijf, voor de volgende cursor
+func (p businessPage) sortValue(business *models.Business) interface{} {
+	switch p.Sort {
+	case "city":
+		return business.City
+	case "created_at":
+		return business.CreatedAt
+	case "distance":
+		if business.DistanceKm != nil {
+			return *business.DistanceKm
+		}
+		return 0.0
+	case "relevance":
+		if business.Relevance != nil {
+			return *business.Relevance
+		}
+		return 0.0
+	default:
+		return business.Name
+	}
+}
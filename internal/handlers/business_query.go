@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRadiusKm = 10.0
+	maxRadiusKm     = 200.0
+)
+
+// earthDistanceSQL - afstand in km tussen het zoekpunt en het bedrijf
+const earthDistanceSQL = "earth_distance(ll_to_earth(?, ?), ll_to_earth(businesses.latitude, businesses.longitude)) / 1000"
+
+// searchTSQuerySQL - zoekvraag in het Duitse en Nederlandse woordenboek
+const searchTSQuerySQL = "(to_tsquery('german', search_normalize(?)) || to_tsquery('dutch', search_normalize(?)))"
+
+// searchRankSQL - relevantie: full-text rank plus trigram gelijkenis (typefouten)
+const searchRankSQL = "(ts_rank(businesses.search_vector, " + searchTSQuerySQL + ") + " +
+	"word_similarity(search_normalize(?), businesses.search_text))"
+
+// businessFilters - filters uit de query string van GET /api/businesses
+type businessFilters struct {
+	Search      services.SearchQuery // ?q=grieks restaurant aken
+	Category    string
+	City        string
+	SubCategory string
+
+	Near     *geo.Point // ?near=lat,lng
+	RadiusKm float64    // ?radius_km=
+	BBox     *geo.BBox  // ?bbox=west,south,east,north
+
+	From *services.TravelOrigin // ?from=7511AB of lat,lng: afstand en reistijd per bedrijf
+
+	OpenAt *time.Time // ?open_now=true of ?open_at=
+}
+
+// writeFilterError - 400 bij ongeldige filters, 503 als postcodes niet opgezocht kunnen worden
+func writeFilterError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrNoPostcodeData) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Postal code lookup unavailable",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "Invalid filter",
+		"details": err.Error(),
+	})
+}
+
+// parseBusinessFilters leest en valideert de filters
+func parseBusinessFilters(c *gin.Context) (businessFilters, error) {
+	filters := businessFilters{
+		Search:      services.ParseSearchQuery(c.Query("q")),
+		Category:    c.Query("category"),    // ?category=restaurant
+		City:        c.Query("city"),        // ?city=Düsseldorf
+		SubCategory: c.Query("subcategory"), // ?subcategory=grieks
+	}
+
+	if near := c.Query("near"); near != "" {
+		point, err := geo.ParsePoint(near)
+		if err != nil {
+			return filters, errors.New("near: " + err.Error())
+		}
+		filters.Near = &point
+
+		filters.RadiusKm = defaultRadiusKm
+		if radius := c.Query("radius_km"); radius != "" {
+			value, err := strconv.ParseFloat(radius, 64)
+			if err != nil || value <= 0 || value > maxRadiusKm {
+				return filters, errors.New("radius_km must be between 0 and 200")
+			}
+			filters.RadiusKm = value
+		}
+	}
+
+	if from := c.Query("from"); from != "" {
+		origin, err := services.ResolveOrigin(c.Request.Context(), from)
+		if err != nil {
+			return filters, fmt.Errorf("from: %w", err)
+		}
+		filters.From = &origin
+	}
+
+	if bbox := c.Query("bbox"); bbox != "" {
+		box, err := geo.ParseBBox(bbox)
+		if err != nil {
+			return filters, errors.New("bbox: " + err.Error())
+		}
+		filters.BBox = &box
+	}
+
+	openAt, err := parseOpenAt(c)
+	if err != nil {
+		return filters, err
+	}
+	filters.OpenAt = openAt
+
+	return filters, nil
+}
+
+// Origin - punt waarvandaan afstanden berekend worden (near, from, anders midden van de bbox)
+func (f businessFilters) Origin() *geo.Point {
+	if f.Near != nil {
+		return f.Near
+	}
+	if f.From != nil {
+		return &f.From.Point
+	}
+	if f.BBox != nil {
+		center := f.BBox.Center()
+		return &center
+	}
+	return nil
+}
+
+// searchRank geeft de relevantie expressie met argumenten
+func (f businessFilters) searchRank() (string, []interface{}) {
+	return searchRankSQL, []interface{}{f.Search.TSQuery, f.Search.TSQuery, strings.Join(f.Search.Terms, " ")}
+}
+
+// Apply voegt de WHERE clauses toe; geo filters gebruiken de GiST indexen
+func (f businessFilters) Apply(query *gorm.DB) *gorm.DB {
+	// Full-text match, of per woord een trigram match (typefouten zoals "Dusseldorf")
+	if !f.Search.IsEmpty() {
+		conditions := []string{"businesses.search_vector @@ " + searchTSQuerySQL}
+		args := []interface{}{f.Search.TSQuery, f.Search.TSQuery}
+		for _, term := range f.Search.Terms {
+			conditions = append(conditions, "search_normalize(?) <% businesses.search_text")
+			args = append(args, term)
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	if f.Category != "" {
+		query = query.Where("businesses.category = ?", f.Category)
+	}
+
+	if f.City != "" {
+		query = query.Where("businesses.city ILIKE ?", "%"+f.City+"%")
+	}
+
+	if f.SubCategory != "" {
+		query = query.Where("businesses.sub_category = ?", f.SubCategory)
+	}
+
+	if f.Near != nil {
+		radiusMeters := f.RadiusKm * 1000
+		query = query.
+			Where("earth_box(ll_to_earth(?, ?), ?) @> ll_to_earth(businesses.latitude, businesses.longitude)",
+				f.Near.Lat, f.Near.Lng, radiusMeters).
+			Where("earth_distance(ll_to_earth(?, ?), ll_to_earth(businesses.latitude, businesses.longitude)) <= ?",
+				f.Near.Lat, f.Near.Lng, radiusMeters)
+	}
+
+	if f.BBox != nil {
+		query = query.Where("point(businesses.longitude, businesses.latitude) <@ box(point(?, ?), point(?, ?))",
+			f.BBox.West, f.BBox.South, f.BBox.East, f.BBox.North)
+	}
+
+	if f.OpenAt != nil {
+		condition, args := openAtCondition(*f.OpenAt)
+		query = query.Where(condition, args...)
+	}
+
+	return query
+}
+
+// WithComputed selecteert het niveau, de afstand tot het zoekpunt en de relevantie (sorteren: zie businessPage)
+func (f businessFilters) WithComputed(query *gorm.DB) *gorm.DB {
+	columns := []string{"businesses.*", services.ListingTierSQL + " AS listing_tier"}
+	var args []interface{}
+
+	if origin := f.Origin(); origin != nil {
+		columns = append(columns, earthDistanceSQL+" AS distance_km")
+		args = append(args, origin.Lat, origin.Lng)
+	}
+
+	if !f.Search.IsEmpty() {
+		rank, rankArgs := f.searchRank()
+		columns = append(columns, rank+" AS relevance")
+		args = append(args, rankArgs...)
+	}
+
+	return query.Select(strings.Join(columns, ", "), args...)
+}
+
+// Response - filters terug in de response
+func (f businessFilters) Response() gin.H {
+	response := gin.H{
+		"q":           f.Search.Raw,
+		"category":    f.Category,
+		"city":        f.City,
+		"subcategory": f.SubCategory,
+	}
+	if f.Near != nil {
+		response["near"] = f.Near
+		response["radius_km"] = f.RadiusKm
+	}
+	if f.From != nil {
+		response["from"] = f.From
+	}
+	if f.BBox != nil {
+		response["bbox"] = f.BBox
+	}
+	if f.OpenAt != nil {
+		response["open_at"] = f.OpenAt.In(services.BerlinLocation).Format(time.RFC3339)
+	}
+	return response
+}
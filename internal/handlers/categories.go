@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CategoryRequest - categorie met namen per taal ({"nl": "Grieks", "de": "Griechisch"})
+type CategoryRequest struct {
+	Slug      string            `json:"slug" binding:"required"`
+	ParentID  *uint             `json:"parent_id"`
+	Icon      string            `json:"icon"`
+	SortOrder int               `json:"sort_order"`
+	IsActive  *bool             `json:"is_active"`
+	Names     map[string]string `json:"names"`
+}
+
+// GetCategories - Publieke categorieboom met namen in de gevraagde taal
+func GetCategories(c *gin.Context) {
+	tree, err := services.CategoryTree(requestLanguage(c), false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"categories": tree,
+	})
+}
+
+// GetAdminCategories - Categorieboom inclusief inactieve categorieën
+func GetAdminCategories(c *gin.Context) {
+	tree, err := services.CategoryTree(requestLanguage(c), true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"categories": tree,
+	})
+}
+
+// validateCategoryRequest normaliseert de slug en controleert ouder en talen.
+// De boom heeft twee niveaus: hoofdcategorie en subcategorie.
+func validateCategoryRequest(c *gin.Context, req *CategoryRequest, current *models.Category) bool {
+	req.Slug = models.CategorySlug(req.Slug)
+	if req.Slug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid slug",
+		})
+		return false
+	}
+
+	var existing models.Category
+	if err := config.DB.Where("slug = ?", req.Slug).First(&existing).Error; err == nil && (current == nil || existing.ID != current.ID) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Category slug already exists",
+			"slug":  req.Slug,
+		})
+		return false
+	}
+
+	for lang := range req.Names {
+		if !services.IsSupportedLanguage(lang) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     "Unsupported language",
+				"language":  lang,
+				"supported": services.SupportedLanguages,
+			})
+			return false
+		}
+	}
+
+	if req.ParentID == nil {
+		return true
+	}
+
+	var parent models.Category
+	if err := config.DB.First(&parent, *req.ParentID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Parent category not found",
+			"id":    *req.ParentID,
+		})
+		return false
+	}
+
+	if parent.ParentID != nil || (current != nil && parent.ID == current.ID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Parent must be a top-level category",
+		})
+		return false
+	}
+
+	// Een hoofdcategorie met subcategorieën kan zelf geen subcategorie worden
+	if current != nil && current.ParentID == nil {
+		var children int64
+		config.DB.Model(&models.Category{}).Where("parent_id = ?", current.ID).Count(&children)
+		if children > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Category has sub categories and cannot be moved",
+			})
+			return false
+		}
+	}
+
+	return true
+}
+
+// saveCategoryNames slaat de namen per taal op (upsert op slug + taal)
+func saveCategoryNames(tx *gorm.DB, slug string, names map[string]string) error {
+	for lang, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		translation := models.CategoryTranslation{Slug: slug, Language: lang, Name: name}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slug"}, {Name: "language"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+		}).Create(&translation).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateCategory - Nieuwe categorie of subcategorie
+func CreateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !validateCategoryRequest(c, &req, nil) {
+		return
+	}
+
+	category := models.Category{
+		Slug:      req.Slug,
+		ParentID:  req.ParentID,
+		Icon:      req.Icon,
+		SortOrder: req.SortOrder,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Children").Create(&category).Error; err != nil {
+			return err
+		}
+		// gorm slaat false over bij default:true en leest daarna true terug
+		if req.IsActive != nil && !*req.IsActive {
+			if err := tx.Model(&category).UpdateColumn("is_active", false).Error; err != nil {
+				return err
+			}
+			category.IsActive = false
+		}
+		return saveCategoryNames(tx, category.Slug, req.Names)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create category",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	category.Names = req.Names
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"category": category,
+		"message":  "Category created successfully",
+	})
+}
+
+// UpdateCategory - Categorie wijzigen. Een nieuwe slug wordt doorgevoerd op
+// bedrijven en vertalingen.
+func UpdateCategory(c *gin.Context) {
+	id, ok := paramID(c, "id", "category")
+	if !ok {
+		return
+	}
+
+	var category models.Category
+	if err := config.DB.First(&category, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Category not found",
+			"id":    c.Param("id"),
+		})
+		return
+	}
+
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !validateCategoryRequest(c, &req, &category) {
+		return
+	}
+
+	oldSlug := category.Slug
+	wasRoot := category.ParentID == nil
+
+	// Verplaatsen naar een andere ouder zou bedrijven met een ongeldige combinatie achterlaten
+	if (req.ParentID == nil) != wasRoot || (req.ParentID != nil && *req.ParentID != *category.ParentID) {
+		var inUse int64
+		config.DB.Model(&models.Business{}).Where("category = ? OR sub_category = ?", oldSlug, oldSlug).Count(&inUse)
+		if inUse > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "Category is used by businesses and cannot be moved",
+				"businesses": inUse,
+			})
+			return
+		}
+	}
+
+	category.Slug = req.Slug
+	category.ParentID = req.ParentID
+	category.Icon = req.Icon
+	category.SortOrder = req.SortOrder
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Children").Save(&category).Error; err != nil {
+			return err
+		}
+
+		if oldSlug != category.Slug {
+			column := "category"
+			if !wasRoot {
+				column = "sub_category"
+			}
+			if err := tx.Model(&models.Business{}).Where(column+" = ?", oldSlug).UpdateColumn(column, category.Slug).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.CategoryTranslation{}).Where("slug = ?", oldSlug).Update("slug", category.Slug).Error; err != nil {
+				return err
+			}
+		}
+
+		return saveCategoryNames(tx, category.Slug, req.Names)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update category",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"category": category,
+		"message":  "Category updated successfully",
+	})
+}
+
+// DeleteCategory - Categorie verwijderen (niet als bedrijven of subcategorieën hem gebruiken)
+func DeleteCategory(c *gin.Context) {
+	id, ok := paramID(c, "id", "category")
+	if !ok {
+		return
+	}
+
+	var category models.Category
+	if err := config.DB.First(&category, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Category not found",
+			"id":    c.Param("id"),
+		})
+		return
+	}
+
+	var children int64
+	config.DB.Model(&models.Category{}).Where("parent_id = ?", category.ID).Count(&children)
+	if children > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Category has sub categories",
+		})
+		return
+	}
+
+	column := "category"
+	if category.ParentID != nil {
+		column = "sub_category"
+	}
+	var inUse int64
+	config.DB.Model(&models.Business{}).Where(column+" = ?", category.Slug).Count(&inUse)
+	if inUse > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Category is used by businesses, deactivate it instead",
+			"businesses": inUse,
+		})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("slug = ?", category.Slug).Delete(&models.CategoryTranslation{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&category).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete category",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Category deleted successfully",
+	})
+}
+
+// validateBusinessCategory controleert category/sub_category tegen de categorieboom
+func validateBusinessCategory(c *gin.Context, req *BusinessRequest) bool {
+	err := services.ValidateBusinessCategory(strings.TrimSpace(req.Category), strings.TrimSpace(req.SubCategory))
+	if err == nil {
+		return true
+	}
+
+	response := gin.H{
+		"error":        err.Error(),
+		"category":     req.Category,
+		"sub_category": req.SubCategory,
+	}
+	if errors.Is(err, services.ErrUnknownCategory) {
+		response["hint"] = "GET /api/categories lists the valid slugs"
+	}
+	c.JSON(http.StatusBadRequest, response)
+	return false
+}
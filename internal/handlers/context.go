@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUser haalt de ingelogde gebruiker uit de context (gezet door AuthMiddleware)
+func currentUser(c *gin.Context) (uint, models.Role, bool) {
+	userID, ok1 := c.Get("user_id")
+	role, ok2 := c.Get("role")
+	if !ok1 || !ok2 {
+		return 0, "", false
+	}
+
+	id, ok1 := userID.(uint)
+	userRole, ok2 := role.(models.Role)
+	return id, userRole, ok1 && ok2
+}
+
+// paramID - numeriek id uit de URL (:name). Altijd via deze helper: een string als
+// conditie aan First/Delete geven leest gorm als SQL. Schrijft zelf een 400 bij een ongeldig id.
+func paramID(c *gin.Context, name, label string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid " + label + " ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// hasPermission - of de aanroeper (rol of API key scope) een permissie heeft
+func hasPermission(c *gin.Context, permission models.Permission) bool {
+	if value, isAPIKey := c.Get("scopes"); isAPIKey {
+		scopes, _ := value.(models.StringList)
+		return scopes.Contains(string(permission))
+	}
+
+	_, role, ok := currentUser(c)
+	return ok && services.HasPermission(role, permission)
+}
+
+// actingUserID - gebruiker namens wie iets wordt vastgelegd. Bij een API key (niet aan
+// een gebruiker gekoppeld) moet die in het request staan (field); anders de ingelogde gebruiker.
+// Schrijft zelf een foutmelding en geeft false terug als dat niet lukt.
+func actingUserID(c *gin.Context, requested *uint, field string) (uint, bool) {
+	if _, isAPIKey := c.Get("api_key_id"); isAPIKey {
+		if requested == nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": field + " is required for API key requests",
+			})
+			return 0, false
+		}
+
+		var user models.User
+		if err := config.DB.Select("id", "is_active").First(&user, *requested).Error; err != nil || !user.IsActive {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": field + " is not an active user",
+			})
+			return 0, false
+		}
+		return user.ID, true
+	}
+
+	userID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// currentVisibility bepaalt welke CRM data de ingelogde gebruiker mag zien.
+// Schrijft zelf een foutmelding en geeft false terug als dat niet lukt.
+func currentVisibility(c *gin.Context) (services.Visibility, bool) {
+	// API keys zijn niet aan een student gekoppeld; de scope bepaalt de toegang
+	if _, isAPIKey := c.Get("api_key_id"); isAPIKey {
+		return services.Visibility{All: true}, true
+	}
+
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return services.Visibility{}, false
+	}
+
+	visibility, err := services.GetVisibility(userID, role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return services.Visibility{}, false
+	}
+
+	return visibility, true
+}
+
+// requestLanguage bepaalt de taal van de response (?lang= of Accept-Language)
+func requestLanguage(c *gin.Context) string {
+	lang := services.ResolveLanguage(c.Query("lang"), c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", lang)
+	c.Header("Vary", "Accept-Language")
+	return lang
+}
+
+// localizeBusinesses vertaalt bedrijven voor de response; schrijft zelf een foutmelding
+func localizeBusinesses(c *gin.Context, businesses []models.Business) bool {
+	if err := services.LocalizeBusinesses(businesses, requestLanguage(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to load translations",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
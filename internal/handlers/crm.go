@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CustomerRequest struct {
+	CompanyName      string                `json:"company_name" binding:"required"`
+	ContactPerson    string                `json:"contact_person" binding:"required"`
+	Email            string                `json:"email" binding:"required,email"`
+	Phone            string                `json:"phone"`
+	Address          string                `json:"address"`
+	City             string                `json:"city"`
+	PostalCode       string                `json:"postal_code"`
+	Country          string                `json:"country"`
+	BusinessType     string                `json:"business_type"`
+	Website          string                `json:"website"`
+	Status           models.CustomerStatus `json:"status"`
+	MonthlyFee       float64               `json:"monthly_fee"`
+	Notes            string                `json:"notes"`
+	AcquiredByUserID *uint                 `json:"acquired_by_user_id"` // Alleen admin mag dit zetten; verplicht bij API keys
+}
+
+type CommunicationRequest struct {
+	Type      models.CommunicationType `json:"type" binding:"required"`
+	Subject   string                   `json:"subject" binding:"required"`
+	Content   string                   `json:"content" binding:"required"`
+	Direction string                   `json:"direction" binding:"required,oneof=inbound outbound"`
+	FromEmail string                   `json:"from_email"`
+	ToEmail   string                   `json:"to_email"`
+	UserID    *uint                    `json:"user_id"` // Alleen bij API keys: wie heeft gecommuniceerd
+}
+
+// CommissionSummary - commissie per student
+type CommissionSummary struct {
+	UserID      uint    `json:"user_id"`
+	FirstName   string  `json:"first_name"`
+	LastName    string  `json:"last_name"`
+	Invoices    int64   `json:"invoices"`
+	Paid        float64 `json:"paid"`
+	Outstanding float64 `json:"outstanding"`
+}
+
+// scopeCustomers beperkt een customer query tot de zichtbare studenten
+func scopeCustomers(query *gorm.DB, visibility services.Visibility) *gorm.DB {
+	if visibility.All {
+		return query
+	}
+	return query.Where("acquired_by_user_id IN ?", visibility.UserIDs)
+}
+
+// findVisibleCustomer haalt een klant op als die zichtbaar is voor de gebruiker
+func findVisibleCustomer(c *gin.Context, visibility services.Visibility) (*models.Customer, bool) {
+	id, ok := paramID(c, "id", "customer")
+	if !ok {
+		return nil, false
+	}
+	var customer models.Customer
+
+	if err := config.DB.Preload("AcquiredBy").First(&customer, id).Error; err != nil || !visibility.Allows(customer.AcquiredByUserID) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Customer not found",
+			"id":    id,
+		})
+		return nil, false
+	}
+
+	return &customer, true
+}
+
+// GetCustomers - Klanten van de gebruiker (en van zijn teamleden)
+func GetCustomers(c *gin.Context) {
+	visibility, ok := currentVisibility(c)
+	if !ok {
+		return
+	}
+
+	var customers []models.Customer
+	query := scopeCustomers(config.DB.Preload("AcquiredBy"), visibility)
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("acquired_by_user_id = ?", userID)
+	}
+
+	if err := query.Order("company_name").Find(&customers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"count":     len(customers),
+		"customers": customers,
+	})
+}
+
+// GetCustomerByID - Klant met communicatie en facturen (alleen met de bijbehorende permissie)
+func GetCustomerByID(c *gin.Context) {
+	visibility, ok := currentVisibility(c)
+	if !ok {
+		return
+	}
+
+	customer, ok := findVisibleCustomer(c, visibility)
+	if !ok {
+		return
+	}
+
+	if hasPermission(c, models.PermCommunicationsRead) {
+		config.DB.Order("created_at DESC").Find(&customer.Communications, "customer_id = ?", customer.ID)
+	}
+	if hasPermission(c, models.PermInvoicesRead) {
+		config.DB.Order("invoice_date DESC").Find(&customer.Invoices, "customer_id = ?", customer.ID)
+	}
+
+	// Betaald niveau vervalt bij een inactieve klant of achterstallige facturen
+	tier, reason, err := services.CustomerListingTier(c.Request.Context(), customer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"customer": customer,
+		"effective_listing_tier": gin.H{
+			"tier":   tier,
+			"reason": reason,
+		},
+	})
+}
+
+// CreateCustomer - Nieuwe klant, standaard toegewezen aan de ingelogde student
+func CreateCustomer(c *gin.Context) {
+	var req CustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := actingUserID(c, req.AcquiredByUserID, "acquired_by_user_id")
+	if !ok {
+		return
+	}
+
+	customer := models.Customer{
+		CompanyName:      req.CompanyName,
+		ContactPerson:    req.ContactPerson,
+		Email:            req.Email,
+		Phone:            req.Phone,
+		Address:          req.Address,
+		City:             req.City,
+		PostalCode:       req.PostalCode,
+		Country:          req.Country,
+		BusinessType:     req.BusinessType,
+		Website:          req.Website,
+		Status:           req.Status,
+		MonthlyFee:       req.MonthlyFee,
+		Notes:            req.Notes,
+		AcquiredByUserID: userID,
+		AcquisitionDate:  time.Now(),
+	}
+
+	if _, role, isUser := currentUser(c); isUser && req.AcquiredByUserID != nil && services.HasPermission(role, models.PermAll) {
+		customer.AcquiredByUserID = *req.AcquiredByUserID
+	}
+
+	// Set defaults
+	if customer.Country == "" {
+		customer.Country = "Germany"
+	}
+	if customer.Status == "" {
+		customer.Status = models.StatusProspect
+	}
+	customer.ListingTier = models.TierBasic // Alleen via SetCustomerListingTier
+
+	if err := config.DB.Create(&customer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create customer",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"customer": customer,
+		"message":  "Customer created successfully",
+	})
+}
+
+// UpdateCustomer - Klantgegevens wijzigen
+func UpdateCustomer(c *gin.Context) {
+	visibility, ok := currentVisibility(c)
+	if !ok {
+		return
+	}
+
+	customer, ok := findVisibleCustomer(c, visibility)
+	if !ok {
+		return
+	}
+
+	var req CustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	customer.CompanyName = req.CompanyName
+	customer.ContactPerson = req.ContactPerson
+	customer.Email = req.Email
+	customer.Phone = req.Phone
+	customer.Address = req.Address
+	customer.City = req.City
+	customer.PostalCode = req.PostalCode
+	customer.BusinessType = req.BusinessType
+	customer.Website = req.Website
+	customer.MonthlyFee = req.MonthlyFee
+	customer.Notes = req.Notes
+	if req.Country != "" {
+		customer.Country = req.Country
+	}
+	if req.Status != "" {
+		customer.Status = req.Status
+	}
+	if req.AcquiredByUserID != nil && visibility.All {
+		customer.AcquiredByUserID = *req.AcquiredByUserID
+	}
+
+	if err := config.DB.Omit("AcquiredBy").Save(customer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update customer",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"customer": customer,
+		"message":  "Customer updated successfully",
+	})
+}
+
+// SetCustomerListingTier - Vermeldingsniveau uit het abonnement zetten (financiën/admin, niet studenten)
+func SetCustomerListingTier(c *gin.Context) {
+	id, ok := paramID(c, "id", "customer")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ListingTier string `json:"listing_tier" binding:"required,oneof=basic featured premium"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var customer models.Customer
+	if err := config.DB.First(&customer, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Customer not found",
+			"id":    id,
+		})
+		return
+	}
+
+	if err := config.DB.Model(&customer).Update("listing_tier", req.ListingTier).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update customer",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tier, reason, err := services.CustomerListingTier(c.Request.Context(), &customer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"customer": customer,
+		"effective_listing_tier": gin.H{
+			"tier":   tier,
+			"reason": reason,
+		},
+	})
+}
+
+// GetCustomerCommunications - Communicatie met een klant
+func GetCustomerCommunications(c *gin.Context) {
+	visibility, ok := currentVisibility(c)
+	if !ok {
+		return
+	}
+
+	customer, ok := findVisibleCustomer(c, visibility)
+	if !ok {
+		return
+	}
+
+	var communications []models.Communication
+	if err := config.DB.Preload("User").
+		Where("customer_id = ?", customer.ID).
+		Order("created_at DESC").
+		Find(&communications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"count":          len(communications),
+		"communications": communications,
+	})
+}
+
+// CreateCommunication - Communicatie vastleggen bij een klant
+func CreateCommunication(c *gin.Context) {
+	visibility, ok := currentVisibility(c)
+	if !ok {
+		return
+	}
+
+	customer, ok := findVisibleCustomer(c, visibility)
+	if !ok {
+		return
+	}
+
+	var req CommunicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID, ok := actingUserID(c, req.UserID, "user_id")
+	if !ok {
+		return
+	}
+
+	communication := models.Communication{
+		CustomerID: customer.ID,
+		UserID:     userID,
+		Type:       req.Type,
+		Subject:    req.Subject,
+		Content:    req.Content,
+		Direction:  req.Direction,
+		FromEmail:  req.FromEmail,
+		ToEmail:    req.ToEmail,
+	}
+
+	if err := config.DB.Omit("Customer", "User").Create(&communication).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create communication",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":       true,
+		"communication": communication,
+		"message":       "Communication created successfully",
+	})
+}
+
+// GetCommission - Commissie per zichtbare student (betaald en openstaand)
+func GetCommission(c *gin.Context) {
+	visibility, ok := currentVisibility(c)
+	if !ok {
+		return
+	}
+
+	var summaries []CommissionSummary
+	query := config.DB.Table("invoices").
+		Select(`invoices.commission_user_id AS user_id,
+			users.first_name, users.last_name,
+			COUNT(*) AS invoices,
+			COALESCE(SUM(invoices.commission_amount) FILTER (WHERE invoices.status = ?), 0) AS paid,
+			COALESCE(SUM(invoices.commission_amount) FILTER (WHERE invoices.status IN ?), 0) AS outstanding`,
+			models.InvoicePaid, []models.InvoiceStatus{models.InvoiceSent, models.InvoiceOverdue}).
+		Joins("JOIN users ON users.id = invoices.commission_user_id").
+		Where("invoices.status <> ?", models.InvoiceCancelled).
+		Group("invoices.commission_user_id, users.first_name, users.last_name").
+		Order("users.last_name")
+
+	if !visibility.All {
+		query = query.Where("invoices.commission_user_id IN ?", visibility.UserIDs)
+	}
+
+	if err := query.Scan(&summaries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var totalPaid, totalOutstanding float64
+	for _, s := range summaries {
+		totalPaid += s.Paid
+		totalOutstanding += s.Outstanding
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"commission": summaries,
+		"totals": gin.H{
+			"paid":        totalPaid,
+			"outstanding": totalOutstanding,
+		},
+	})
+}
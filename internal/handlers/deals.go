@@ -0,0 +1,450 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultDealLimit  = 50
+	maxDealLimit      = 100
+	defaultReportDays = 30
+)
+
+// DealRequest - velden van een deal (admin)
+type DealRequest struct {
+	Title         string    `json:"title" binding:"required,max=120"`
+	Description   string    `json:"description"`
+	Terms         string    `json:"terms"`
+	StartsAt      time.Time `json:"starts_at" binding:"required"`
+	EndsAt        time.Time `json:"ends_at" binding:"required"`
+	CodeMode      string    `json:"code_mode" binding:"omitempty,oneof=none shared unique"`
+	Code          string    `json:"code" binding:"max=20"`
+	MaxPerVisitor *int      `json:"max_per_visitor" binding:"omitempty,min=0"` // Leeg: 1 per bezoeker
+	MaxTotal      int       `json:"max_total" binding:"min=0"`
+	IsActive      *bool     `json:"is_active"`
+}
+
+// apply zet de request velden op de deal
+func (req *DealRequest) apply(deal *models.Deal) error {
+	if err := services.ValidateDealWindow(req.StartsAt, req.EndsAt); err != nil {
+		return err
+	}
+
+	deal.Title = strings.TrimSpace(req.Title)
+	deal.Description = req.Description
+	deal.Terms = req.Terms
+	deal.StartsAt = req.StartsAt
+	deal.EndsAt = req.EndsAt
+	deal.CodeMode = req.CodeMode
+	deal.Code = strings.TrimSpace(req.Code)
+	deal.MaxTotal = req.MaxTotal
+
+	if deal.CodeMode == "" {
+		deal.CodeMode = models.DealCodeNone
+	}
+	if deal.CodeMode == models.DealCodeShared && deal.Code == "" {
+		return errors.New("code is required for code_mode shared")
+	}
+	if deal.CodeMode != models.DealCodeShared {
+		deal.Code = ""
+	}
+
+	deal.MaxPerVisitor = 1
+	if req.MaxPerVisitor != nil {
+		deal.MaxPerVisitor = *req.MaxPerVisitor
+	}
+	deal.IsActive = req.IsActive == nil || *req.IsActive
+
+	return nil
+}
+
+// requireActiveCustomer - deals alleen voor bedrijven met een actieve klant
+func requireActiveCustomer(c *gin.Context, business *models.Business) bool {
+	active, err := services.BusinessHasActiveCustomer(business)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return false
+	}
+	if !active {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":       services.ErrCustomerNotActive.Error(),
+			"business_id": business.ID,
+		})
+		return false
+	}
+	return true
+}
+
+// GetDeals - Lopende deals (zelfde filters als GET /api/businesses: category, city, near, bbox, ...)
+func GetDeals(c *gin.Context) {
+	filters, err := parseBusinessFilters(c)
+	if err != nil {
+		writeFilterError(c, err)
+		return
+	}
+
+	limit := defaultDealLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxDealLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be between 1 and " + strconv.Itoa(maxDealLimit),
+			})
+			return
+		}
+		limit = n
+	}
+
+	deals := []models.Deal{}
+	err = filters.Apply(services.CurrentDeals(config.DB, time.Now())).
+		Preload("Business", services.WithListingTier).
+		Order("deals.ends_at, deals.id").
+		Limit(limit).
+		Find(&deals).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	services.HideDealCodes(deals)
+
+	// Bedrijven vertalen zoals in de lijst
+	businesses := make([]models.Business, len(deals))
+	for i := range deals {
+		businesses[i] = *deals[i].Business
+	}
+	if !localizeBusinesses(c, businesses) {
+		return
+	}
+	if filters.From != nil {
+		services.AttachTravel(filters.From.Point, businesses)
+	}
+	for i := range deals {
+		deals[i].Business = &businesses[i]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(deals),
+		"deals":   deals,
+		"filters": filters.Response(),
+	})
+}
+
+// GetBusinessDeals - Lopende deals van één bedrijf
+func GetBusinessDeals(c *gin.Context) {
+	deals := []models.Deal{}
+	err := services.CurrentDeals(config.DB, time.Now()).
+		Where("deals.business_id = ?", c.Param("id")).
+		Order("deals.ends_at, deals.id").
+		Find(&deals).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	services.HideDealCodes(deals)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(deals),
+		"deals":   deals,
+	})
+}
+
+// RedeemDeal - Deal inwisselen. visitor_id is een willekeurig ID dat de site per
+// bezoeker bewaart (localStorage, voor de statistieken). Deals met een limiet per
+// bezoeker vereisen een login; het IP adres telt alleen voor de rate limiter.
+func RedeemDeal(c *gin.Context) {
+	id, ok := paramID(c, "id", "deal")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		VisitorID string `json:"visitor_id" binding:"required,min=8,max=100"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var userID *uint
+	if uid, _, ok := currentUser(c); ok {
+		userID = &uid
+	}
+
+	result, err := services.RedeemDeal(c.Request.Context(), id, req.VisitorID, userID)
+	switch {
+	case errors.Is(err, services.ErrDealLoginRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "id": id})
+		return
+	case errors.Is(err, services.ErrDealNotAvailable):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "id": id})
+		return
+	case errors.Is(err, services.ErrDealSoldOut), errors.Is(err, services.ErrDealVisitorLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "id": id})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to redeem deal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":    true,
+		"redemption": result.Redemption,
+		"code":       result.Code,
+		"remaining":  result.Remaining,
+	})
+}
+
+// GetAdminBusinessDeals - Alle deals van een bedrijf (ook verlopen) met aantal inwisselingen
+func GetAdminBusinessDeals(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	deals := []models.Deal{}
+	err := services.WithRedemptionCount(config.DB.Model(&models.Deal{})).
+		Where("deals.business_id = ?", business.ID).
+		Order("deals.starts_at DESC, deals.id DESC").
+		Find(&deals).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(deals),
+		"deals":   deals,
+	})
+}
+
+// CreateDeal - Nieuwe deal voor een bedrijf met een actieve klant
+func CreateDeal(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	var req DealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !requireActiveCustomer(c, business) {
+		return
+	}
+
+	deal := models.Deal{BusinessID: business.ID}
+	if err := req.apply(&deal); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := config.DB.Omit("Business").Create(&deal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create deal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"deal":    deal,
+		"message": "Deal created successfully",
+	})
+}
+
+// findDeal haalt een deal op voor admin endpoints
+func findDeal(c *gin.Context) (*models.Deal, bool) {
+	id, ok := paramID(c, "id", "deal")
+	if !ok {
+		return nil, false
+	}
+	var deal models.Deal
+
+	if err := config.DB.First(&deal, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Deal not found",
+			"id":    id,
+		})
+		return nil, false
+	}
+
+	return &deal, true
+}
+
+// UpdateDeal - Deal wijzigen (alle velden)
+func UpdateDeal(c *gin.Context) {
+	deal, ok := findDeal(c)
+	if !ok {
+		return
+	}
+
+	var req DealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := req.apply(deal); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Weer actief zetten mag alleen zolang de klant actief is
+	if deal.IsActive {
+		var business models.Business
+		if err := config.DB.First(&business, deal.BusinessID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Business not found",
+				"id":    deal.BusinessID,
+			})
+			return
+		}
+		if !requireActiveCustomer(c, &business) {
+			return
+		}
+	}
+
+	if err := config.DB.Omit("Business").Save(deal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update deal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"deal":    deal,
+		"message": "Deal updated successfully",
+	})
+}
+
+// DeleteDeal - Deal en inwisselingen verwijderen (voor stoppen: is_active=false, dan blijft het rapport)
+func DeleteDeal(c *gin.Context) {
+	deal, ok := findDeal(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("deal_id = ?", deal.ID).Delete(&models.DealRedemption{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(deal).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete deal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Deal deleted successfully",
+	})
+}
+
+// GetDealReport - Inwisselingen per deal en per dag (?from=YYYY-MM-DD&to=YYYY-MM-DD, standaard 30 dagen)
+func GetDealReport(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	today := time.Now().In(services.BerlinLocation)
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, services.BerlinLocation).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -defaultReportDays)
+
+	for param, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := c.Query(param); value != "" {
+			day, err := time.ParseInLocation("2006-01-02", value, services.BerlinLocation)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": param + " must be YYYY-MM-DD",
+				})
+				return
+			}
+			if param == "to" {
+				day = day.AddDate(0, 0, 1) // Tot en met
+			}
+			*target = day
+		}
+	}
+	if !to.After(from) || to.Sub(from) > 366*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "from must be before to, at most a year apart",
+		})
+		return
+	}
+
+	reports, err := services.BusinessDealReport(c.Request.Context(), business.ID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var redemptions int64
+	for _, report := range reports {
+		redemptions += report.Redemptions
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"business_id": business.ID,
+		"from":        from.Format("2006-01-02"),
+		"to":          to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"redemptions": redemptions,
+		"deals":       reports,
+	})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+type DismissDuplicateRequest struct {
+	BusinessIDs []uint `json:"business_ids" binding:"required,len=2"`
+}
+
+type MergeBusinessRequest struct {
+	DuplicateID       uint     `json:"duplicate_id" binding:"required"`
+	TakeFromDuplicate []string `json:"take_from_duplicate"` // Velden waarvoor de waarde van het dubbele bedrijf wint
+}
+
+// GetDuplicateBusinesses - Mogelijke dubbele bedrijven (?min_score=0.6&limit=50&business_id=)
+func GetDuplicateBusinesses(c *gin.Context) {
+	minScore, err := strconv.ParseFloat(c.DefaultQuery("min_score", "0.6"), 64)
+	if err != nil || minScore < 0 || minScore > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "min_score must be between 0 and 1",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit must be between 1 and 500",
+		})
+		return
+	}
+
+	var businessID uint64
+	if value := c.Query("business_id"); value != "" {
+		if businessID, err = strconv.ParseUint(value, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid business_id",
+			})
+			return
+		}
+	}
+
+	candidates, err := services.FindDuplicates(minScore, limit, uint(businessID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"count":      len(candidates),
+		"duplicates": candidates,
+	})
+}
+
+// DismissDuplicate - Paar markeren als "geen dubbele" (komt niet meer in de lijst)
+func DismissDuplicate(c *gin.Context) {
+	var req DismissDuplicateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	a, b := req.BusinessIDs[0], req.BusinessIDs[1]
+	if a == b {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "business_ids must be two different businesses",
+		})
+		return
+	}
+	if a > b {
+		a, b = b, a
+	}
+
+	dismissal := models.DuplicateDismissal{BusinessAID: a, BusinessBID: b}
+	if userID, _, ok := currentUser(c); ok {
+		dismissal.DismissedByUserID = &userID
+	}
+
+	if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&dismissal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to dismiss duplicate",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Duplicate dismissed",
+	})
+}
+
+// MergeBusinesses - Dubbel bedrijf samenvoegen met dit bedrijf (:id blijft over).
+// Klant, foto's, openingstijden en vertalingen gaan naar het overblijvende bedrijf.
+func MergeBusinesses(c *gin.Context) {
+	survivor, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	var req MergeBusinessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	merged, err := services.MergeBusinesses(c.Request.Context(), survivor.ID, req.DuplicateID, req.TakeFromDuplicate)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrMergeBusinessAbsent):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrMergeSameBusiness), errors.Is(err, services.ErrUnknownMergeField):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrAlreadyMerged), errors.Is(err, services.ErrMergeCustomerClash):
+			status = http.StatusConflict
+		}
+
+		response := gin.H{
+			"error":   "Failed to merge businesses",
+			"details": err.Error(),
+		}
+		if errors.Is(err, services.ErrUnknownMergeField) {
+			fields := make([]string, 0, len(services.MergeFields))
+			for field := range services.MergeFields {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			response["fields"] = fields
+		}
+		c.JSON(status, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"business": merged,
+		"message":  "Businesses merged successfully",
+	})
+}
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxFuelFeedBytes       = 10 << 20
+	defaultFuelRadiusKm    = 25.0
+	defaultFuelLimit       = 10
+	maxFuelLimit           = 50
+	defaultTankLiters      = 50.0
+	defaultFuelDays        = 30
+	maxUnmatchedInResponse = 100
+)
+
+// fuelSource - wie de prijzen aanlevert (API key naam of admin gebruiker)
+func fuelSource(c *gin.Context) string {
+	if name, ok := c.Get("api_key_name"); ok {
+		if value, ok := name.(string); ok {
+			return "apikey:" + value
+		}
+	}
+	if userID, _, ok := currentUser(c); ok {
+		return "admin:" + strconv.FormatUint(uint64(userID), 10)
+	}
+	return "admin"
+}
+
+// IngestFuelPrices - Prijzen aanleveren in Tankerkönig formaat (prices.php, list.php of detail.php).
+// Stations worden gekoppeld via fuel_station_id van het bedrijf. ?observed_at= (RFC3339) voor oude data.
+func IngestFuelPrices(c *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxFuelFeedBytes+1))
+	if err != nil || len(data) > maxFuelFeedBytes {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid or too large request body",
+		})
+		return
+	}
+
+	observedAt := time.Now()
+	if value := c.Query("observed_at"); value != "" {
+		observedAt, err = time.Parse(time.RFC3339, value)
+		if err != nil || observedAt.After(time.Now().Add(5*time.Minute)) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "observed_at must be an RFC3339 time, not in the future",
+			})
+			return
+		}
+	}
+
+	feed, err := services.ParseTankerkoenig(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid fuel price feed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := services.IngestFuelPrices(c.Request.Context(), feed, fuelSource(c), observedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to store fuel prices",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	unmatchedCount := len(result.Unmatched)
+	if unmatchedCount > maxUnmatchedInResponse {
+		result.Unmatched = result.Unmatched[:maxUnmatchedInResponse]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"result":          result,
+		"unmatched_count": unmatchedCount,
+	})
+}
+
+// GetCheapestFuel - Goedkoopste tankstations in de buurt met besparing t.o.v. de
+// Nederlandse referentieprijs (?fuel=e10&near=lat,lng of &from=7511AB, radius_km, limit, tank_liters)
+func GetCheapestFuel(c *gin.Context) {
+	fuel := c.DefaultQuery("fuel", models.FuelE10)
+	if !models.IsValidFuelType(fuel) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Unknown fuel type",
+			"fuel_types": models.FuelTypes,
+		})
+		return
+	}
+
+	var origin geo.Point
+	switch {
+	case c.Query("near") != "":
+		point, err := geo.ParsePoint(c.Query("near"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid near",
+				"details": err.Error(),
+			})
+			return
+		}
+		origin = point
+	case c.Query("from") != "":
+		resolved, err := services.ResolveOrigin(c.Request.Context(), c.Query("from"))
+		if err != nil {
+			writeOriginError(c, err)
+			return
+		}
+		origin = resolved.Point
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "near (lat,lng) or from (Dutch postal code) is required",
+		})
+		return
+	}
+
+	radiusKm := defaultFuelRadiusKm
+	if value := c.Query("radius_km"); value != "" {
+		radius, err := strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 || radius > maxRadiusKm {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "radius_km must be between 0 and 200",
+			})
+			return
+		}
+		radiusKm = radius
+	}
+
+	limit := defaultFuelLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxFuelLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be between 1 and " + strconv.Itoa(maxFuelLimit),
+			})
+			return
+		}
+		limit = n
+	}
+
+	tankLiters := defaultTankLiters
+	if value := c.Query("tank_liters"); value != "" {
+		liters, err := strconv.ParseFloat(value, 64)
+		if err != nil || liters <= 0 || liters > 200 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "tank_liters must be between 0 and 200",
+			})
+			return
+		}
+		tankLiters = liters
+	}
+
+	offers, err := services.CheapestFuel(c.Request.Context(), fuel, origin, radiusKm, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	references, err := services.FuelReferencePrices(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var referencePrice interface{}
+	if reference, ok := references[fuel]; ok {
+		services.ApplyFuelSavings(offers, reference, tankLiters)
+		referencePrice = reference
+	}
+
+	// Bedrijven vertalen en thumbnails zoals in de lijst
+	businesses := make([]models.Business, len(offers))
+	for i := range offers {
+		businesses[i] = offers[i].Business
+	}
+	if !localizeBusinesses(c, businesses) {
+		return
+	}
+	if err := services.AttachThumbnails(businesses); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+	for i := range offers {
+		offers[i].Business = businesses[i]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"fuel_type":       fuel,
+		"origin":          origin,
+		"radius_km":       radiusKm,
+		"tank_liters":     tankLiters,
+		"reference_price": referencePrice, // null: geen referentieprijs ingesteld, dan geen besparing
+		"count":           len(offers),
+		"stations":        offers,
+	})
+}
+
+// GetBusinessFuelPrices - Prijshistorie van een tankstation (?fuel=e10&days=30)
+func GetBusinessFuelPrices(c *gin.Context) {
+	id, ok := paramID(c, "id", "business")
+	if !ok {
+		return
+	}
+	var business models.Business
+	if err := config.DB.Select("id").Where("is_active = ?", true).First(&business, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Business not found",
+			"id":    id,
+		})
+		return
+	}
+
+	days := defaultFuelDays
+	if value := c.Query("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "days must be between 1 and 365",
+			})
+			return
+		}
+		days = n
+	}
+
+	query := config.DB.Where("business_id = ? AND observed_at >= ?", business.ID, time.Now().AddDate(0, 0, -days))
+	if fuel := c.Query("fuel"); fuel != "" {
+		if !models.IsValidFuelType(fuel) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Unknown fuel type",
+				"fuel_types": models.FuelTypes,
+			})
+			return
+		}
+		query = query.Where("fuel_type = ?", fuel)
+	}
+
+	history := []models.FuelPrice{}
+	if err := query.Order("fuel_type, observed_at").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	current, err := services.LatestFuelPrices(c.Request.Context(), []uint{business.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"business_id": business.ID,
+		"days":        days,
+		"current":     current,
+		"history":     history, // Alleen wijzigingen: een prijs geldt tot de volgende
+	})
+}
+
+// GetFuelReferencePrices - Nederlandse referentieprijzen
+func GetFuelReferencePrices(c *gin.Context) {
+	references := []models.FuelReferencePrice{}
+	if err := config.DB.Order("fuel_type").Find(&references).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"references": references,
+	})
+}
+
+// SetFuelReferencePrice - Referentieprijs voor een brandstofsoort instellen
+func SetFuelReferencePrice(c *gin.Context) {
+	fuel := c.Param("fuel")
+	if !models.IsValidFuelType(fuel) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Unknown fuel type",
+			"fuel_types": models.FuelTypes,
+		})
+		return
+	}
+
+	var req struct {
+		Price float64 `json:"price" binding:"required,gt=0,lt=10"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	reference := models.FuelReferencePrice{FuelType: fuel, Price: req.Price}
+	err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "fuel_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price", "updated_at"}),
+	}).Create(&reference).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save reference price",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"reference": reference,
+	})
+}
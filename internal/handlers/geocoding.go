@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckBusinessLocations - Alle coördinaten opnieuw controleren tegen de postcode
+// (ontbrekende worden ingevuld). Daarna: GET /admin/businesses?geocode_status=mismatch
+func CheckBusinessLocations(c *gin.Context) {
+	counts, err := services.CheckBusinessLocations(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Location check failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"counts":  counts,
+	})
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/importer"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
+)
+
+const maxImportBytes = 20 << 20
+
+// Acties in het importrapport
+const (
+	importCreate    = "create"
+	importUpdate    = "update"
+	importUnchanged = "unchanged"
+	importMerged    = "merged" // Samengevoegd met een ander bedrijf; rij overgeslagen
+	importError     = "error"
+)
+
+// ImportRowResult - resultaat per rij
+type ImportRowResult struct {
+	Row        int      `json:"row"`
+	Action     string   `json:"action"`
+	ExternalID string   `json:"external_id,omitempty"`
+	Name       string   `json:"name"`
+	BusinessID uint     `json:"business_id,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+	Location   string   `json:"location,omitempty"` // GeocodeStatus na import (ok, geocoded, mismatch, ...)
+
+	request  BusinessRequest
+	existing *models.Business
+	business models.Business // Request toegepast en gegeocodeerd; wordt zo opgeslagen
+}
+
+// applyImportValues zet de gemapte waarden op een request. Lege cellen laten de
+// huidige waarde staan, zodat een lijst met minder kolommen niets wist.
+func applyImportValues(req *BusinessRequest, values map[string]string) []string {
+	var errs []string
+
+	set := func(field string, target *string) {
+		if value := values[field]; value != "" {
+			*target = value
+		}
+	}
+
+	set("name", &req.Name)
+	set("address", &req.Address)
+	set("city", &req.City)
+	set("postal_code", &req.PostalCode)
+	set("country", &req.Country)
+	set("phone", &req.Phone)
+	set("website", &req.Website)
+	set("email", &req.Email)
+	set("description", &req.Description)
+
+	if value := values["state"]; value != "" {
+		req.State = strings.ToUpper(value)
+	}
+	if value := values["category"]; value != "" {
+		req.Category = models.NormalizeCategory(value)
+	}
+	if value := values["sub_category"]; value != "" {
+		req.SubCategory = models.NormalizeCategory(value)
+	}
+
+	for field, target := range map[string]*float64{"latitude": &req.Latitude, "longitude": &req.Longitude} {
+		if value := values[field]; value != "" {
+			parsed, err := importer.ParseCoordinate(value)
+			if err != nil {
+				errs = append(errs, field+": not a number")
+				continue
+			}
+			*target = parsed
+		}
+	}
+
+	return errs
+}
+
+// validateImportRow - dezelfde regels als CreateBusiness
+func validateImportRow(req *BusinessRequest) []string {
+	var errs []string
+
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		errs = append(errs, strings.Split(err.Error(), "\n")...)
+	}
+	if req.Category != "" {
+		if err := services.ValidateBusinessCategory(req.Category, req.SubCategory); err != nil {
+			errs = append(errs, err.Error()+": "+req.Category+"/"+req.SubCategory)
+		}
+	}
+
+	return errs
+}
+
+// ImportBusinesses - Bedrijven importeren uit CSV of XLSX.
+//
+// Multipart velden: file, source (bijv. "ihk-aachen"), mapping (optioneel JSON
+// veld -> kolomnaam) en dry_run (standaard true: alleen het rapport). Rijen met een
+// external_id die al van deze bron geïmporteerd zijn worden bijgewerkt.
+func ImportBusinesses(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes+1<<20)
+
+	// Eerst het bestand: een te grote upload laat ook de andere velden leeg
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":     "File too large",
+				"max_bytes": maxImportBytes,
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "file is required",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if header.Size > maxImportBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":     "File too large",
+			"max_bytes": maxImportBytes,
+		})
+		return
+	}
+
+	dryRun := c.DefaultPostForm("dry_run", "true") != "false"
+
+	source := models.CategorySlug(c.PostForm("source"))
+	if source == "" || len(source) > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "source is required (e.g. ihk-aachen)",
+		})
+		return
+	}
+
+	var explicit map[string]string
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &explicit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "mapping must be a JSON object (field -> column)",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to read file",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to read file",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	sheet, err := importer.ReadFile(header.Filename, data)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, importer.ErrUnsupportedFormat) {
+			status = http.StatusUnsupportedMediaType
+		}
+		c.JSON(status, gin.H{
+			"error":    "Failed to read spreadsheet",
+			"details":  err.Error(),
+			"max_rows": importer.MaxRows,
+		})
+		return
+	}
+
+	mapping, err := importer.BuildMapping(sheet.Header, explicit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid column mapping",
+			"details": err.Error(),
+			"columns": sheet.Header,
+			"fields":  importer.Fields,
+		})
+		return
+	}
+
+	// Eerder geïmporteerde bedrijven van deze bron
+	var existing []models.Business
+	if err := config.DB.Where("import_source = ? AND external_id IS NOT NULL", source).Find(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+	byExternalID := make(map[string]*models.Business, len(existing))
+	for i := range existing {
+		byExternalID[*existing[i].ExternalID] = &existing[i]
+	}
+
+	results := make([]ImportRowResult, 0, len(sheet.Rows))
+	summary := map[string]int{importCreate: 0, importUpdate: 0, importUnchanged: 0, importMerged: 0, importError: 0}
+	seen := map[string]int{}
+
+	for _, row := range sheet.Rows {
+		values := importer.Values(row, mapping)
+		result := ImportRowResult{Row: row.Number, ExternalID: values["external_id"]}
+
+		if result.ExternalID != "" {
+			if first, duplicate := seen[result.ExternalID]; duplicate {
+				result.Errors = append(result.Errors, "duplicate external_id (also on row "+strconv.Itoa(first)+")")
+			}
+			seen[result.ExternalID] = row.Number
+			result.existing = byExternalID[result.ExternalID]
+		}
+
+		if result.existing != nil && result.existing.MergedIntoID != nil {
+			result.Action = importMerged
+			result.Name = result.existing.Name
+			result.BusinessID = *result.existing.MergedIntoID
+			summary[result.Action]++
+			results = append(results, result)
+			continue
+		}
+
+		if result.existing != nil {
+			result.request = requestFromBusiness(result.existing)
+			result.BusinessID = result.existing.ID
+		}
+
+		result.Errors = append(result.Errors, applyImportValues(&result.request, values)...)
+		result.Errors = append(result.Errors, validateImportRow(&result.request)...)
+		result.Name = result.request.Name
+
+		switch {
+		case len(result.Errors) > 0:
+			result.Action = importError
+		case result.existing == nil:
+			result.Action = importCreate
+		case result.request == requestFromBusiness(result.existing):
+			result.Action = importUnchanged
+		default:
+			result.Action = importUpdate
+		}
+
+		// Coördinaten invullen/controleren zoals bij CreateBusiness
+		if result.Action == importCreate || result.Action == importUpdate {
+			if result.existing != nil {
+				result.business = *result.existing
+			} else {
+				result.business = models.Business{ImportSource: source, IsActive: true}
+				if result.ExternalID != "" {
+					externalID := result.ExternalID
+					result.business.ExternalID = &externalID
+				}
+			}
+			result.request.apply(&result.business)
+			if err := services.GeocodeBusiness(c.Request.Context(), &result.business); err != nil {
+				result.Action = importError
+				result.Errors = append(result.Errors, "geocoding: "+err.Error())
+			} else {
+				result.Location = result.business.GeocodeStatus
+			}
+		}
+
+		summary[result.Action]++
+		results = append(results, result)
+	}
+
+	if !dryRun {
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			for i := range results {
+				result := &results[i]
+
+				// Coördinaten en status uit het rapport hierboven, niet opnieuw geocoderen
+				switch result.Action {
+				case importCreate:
+					if err := tx.Omit("Customer").Create(&result.business).Error; err != nil {
+						return err
+					}
+					result.BusinessID = result.business.ID
+				case importUpdate:
+					if err := tx.Omit("Customer").Save(&result.business).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Import failed, nothing was saved",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"dry_run": dryRun,
+		"source":  source,
+		"mapping": importer.MappingResponse(sheet.Header, mapping),
+		"summary": summary,
+		"rows":    results,
+	})
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS - Publieke sleutels om onze JWTs te verifiëren
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"keys": services.JWKS(),
+	})
+}
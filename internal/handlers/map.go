@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxGeoJSONFeatures  = 5000
+	maxClusterZoom      = 15 // Vanaf dit zoomniveau losse markers
+	clusterCellsPerTile = 4  // Cellen per 256px tile (~64px per cluster)
+)
+
+// clusterCell - aantal per categorie in één gridcel (uit de database)
+type clusterCell struct {
+	CellX      int64
+	CellY      int64
+	Category   string
+	Count      int64
+	Featured   int64
+	Latitude   float64
+	Longitude  float64
+	BusinessID uint
+}
+
+// mapCluster - samengevoegde cel met categorieverdeling
+type mapCluster struct {
+	Count      int64
+	Featured   int64 // Featured en premium bedrijven in de cluster
+	LatSum     float64
+	LngSum     float64
+	Categories map[string]int64
+	BusinessID uint
+}
+
+// businessFeature zet een bedrijf om naar een GeoJSON feature
+func businessFeature(business models.Business) geo.Feature {
+	return geo.NewPointFeature(business.ID, geo.Point{Lat: business.Latitude, Lng: business.Longitude}, map[string]interface{}{
+		"name":              business.Name,
+		"category":          business.Category,
+		"category_name":     business.CategoryName,
+		"sub_category":      business.SubCategory,
+		"sub_category_name": business.SubCategoryName,
+		"address":           business.Address,
+		"city":              business.City,
+		"phone":             business.Phone,
+		"website":           business.Website,
+		"listing_tier":      business.ListingTier,
+		"highlight":         models.ListingTierHighlighted(business.ListingTier),
+	})
+}
+
+// writeGeoJSON stuurt een FeatureCollection met het juiste content type
+func writeGeoJSON(c *gin.Context, collection geo.FeatureCollection) {
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, collection)
+}
+
+// GetBusinessesGeoJSON - Bedrijven als GeoJSON FeatureCollection (zelfde filters als de lijst)
+func GetBusinessesGeoJSON(c *gin.Context) {
+	filters, err := parseBusinessFilters(c)
+	if err != nil {
+		writeFilterError(c, err)
+		return
+	}
+
+	var businesses []models.Business
+	query := filters.Apply(services.WithListingTier(config.DB.Model(&models.Business{})).Where("businesses.is_active = ?", true))
+
+	// Uitgelichte bedrijven eerst, zodat ze bij afkappen niet wegvallen
+	if err := query.Order(services.ListingHighlightSQL + " DESC, businesses.id").Limit(maxGeoJSONFeatures + 1).Find(&businesses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	truncated := len(businesses) > maxGeoJSONFeatures
+	if truncated {
+		businesses = businesses[:maxGeoJSONFeatures]
+	}
+
+	if !localizeBusinesses(c, businesses) {
+		return
+	}
+
+	features := make([]geo.Feature, 0, len(businesses))
+	for _, business := range businesses {
+		features = append(features, businessFeature(business))
+	}
+
+	collection := geo.NewFeatureCollection(features)
+	if filters.BBox != nil {
+		collection.BBox = filters.BBox.Slice()
+	}
+	collection.Meta = gin.H{
+		"count":     len(features),
+		"truncated": truncated, // Gebruik dan de clusters of een kleinere bbox
+	}
+
+	writeGeoJSON(c, collection)
+}
+
+// GetBusinessClusters - Geclusterde bedrijven per zoomniveau binnen een bbox.
+// Clusters hebben een aantal en verdeling per categorie; losse bedrijven worden
+// als gewone feature teruggegeven.
+func GetBusinessClusters(c *gin.Context) {
+	filters, err := parseBusinessFilters(c)
+	if err != nil {
+		writeFilterError(c, err)
+		return
+	}
+
+	if filters.BBox == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "bbox is required",
+		})
+		return
+	}
+
+	zoom, err := strconv.Atoi(c.DefaultQuery("zoom", "8"))
+	if err != nil || zoom < 0 || zoom > 22 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "zoom must be between 0 and 22",
+		})
+		return
+	}
+
+	// Ingezoomd: geen clustering meer nodig
+	if zoom >= maxClusterZoom {
+		GetBusinessesGeoJSON(c)
+		return
+	}
+
+	cellSize := 360 / (math.Pow(2, float64(zoom)) * clusterCellsPerTile)
+
+	var cells []clusterCell
+	err = filters.Apply(config.DB.Model(&models.Business{}).Where("businesses.is_active = ?", true)).
+		Select(`FLOOR(businesses.longitude / ?) AS cell_x, FLOOR(businesses.latitude / ?) AS cell_y,
+			businesses.category, COUNT(*) AS count, COUNT(*) FILTER (WHERE `+services.ListingHighlightSQL+`) AS featured,
+			AVG(businesses.latitude) AS latitude, AVG(businesses.longitude) AS longitude,
+			MIN(businesses.id) AS business_id`, cellSize, cellSize).
+		Group("cell_x, cell_y, businesses.category").
+		Scan(&cells).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Categorieën per cel samenvoegen
+	type cellKey struct{ X, Y int64 }
+	clusters := map[cellKey]*mapCluster{}
+	var order []cellKey
+
+	for _, cell := range cells {
+		key := cellKey{cell.CellX, cell.CellY}
+		cluster, ok := clusters[key]
+		if !ok {
+			cluster = &mapCluster{Categories: map[string]int64{}, BusinessID: cell.BusinessID}
+			clusters[key] = cluster
+			order = append(order, key)
+		}
+
+		cluster.Count += cell.Count
+		cluster.Featured += cell.Featured
+		cluster.LatSum += cell.Latitude * float64(cell.Count)
+		cluster.LngSum += cell.Longitude * float64(cell.Count)
+		cluster.Categories[cell.Category] += cell.Count
+	}
+
+	// Losse bedrijven in één keer ophalen
+	var singleIDs []uint
+	for _, key := range order {
+		if clusters[key].Count == 1 {
+			singleIDs = append(singleIDs, clusters[key].BusinessID)
+		}
+	}
+
+	singles := map[uint]models.Business{}
+	if len(singleIDs) > 0 {
+		var businesses []models.Business
+		services.WithListingTier(config.DB).Where("id IN ?", singleIDs).Find(&businesses)
+		if !localizeBusinesses(c, businesses) {
+			return
+		}
+		for _, business := range businesses {
+			singles[business.ID] = business
+		}
+	}
+
+	features := make([]geo.Feature, 0, len(order))
+	var total int64
+	for _, key := range order {
+		cluster := clusters[key]
+		total += cluster.Count
+
+		if business, ok := singles[cluster.BusinessID]; ok && cluster.Count == 1 {
+			features = append(features, businessFeature(business))
+			continue
+		}
+
+		center := geo.Point{
+			Lat: cluster.LatSum / float64(cluster.Count),
+			Lng: cluster.LngSum / float64(cluster.Count),
+		}
+		features = append(features, geo.NewPointFeature(
+			"cluster-"+strconv.FormatInt(key.X, 10)+"-"+strconv.FormatInt(key.Y, 10),
+			center,
+			map[string]interface{}{
+				"cluster":    true,
+				"count":      cluster.Count,
+				"featured":   cluster.Featured,
+				"categories": cluster.Categories,
+			},
+		))
+	}
+
+	collection := geo.NewFeatureCollection(features)
+	collection.BBox = filters.BBox.Slice()
+	collection.Meta = gin.H{
+		"zoom":      zoom,
+		"cell_size": cellSize,
+		"total":     total,
+	}
+
+	writeGeoJSON(c, collection)
+}
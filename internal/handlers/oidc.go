@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"projectpeterperplexity/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// frontendURL - waar de gebruiker na een OIDC login heen gaat
+func frontendURL() string {
+	if url := os.Getenv("OIDC_FRONTEND_REDIRECT"); url != "" {
+		return url
+	}
+	return "https://burogrenstoerisme.nl/dashboard"
+}
+
+// oidcStateCookie - getekende login state; alleen meegestuurd naar de OIDC routes
+const oidcStateCookie = "oidc_state"
+
+// setOIDCStateCookie zet (of met maxAge -1: verwijdert) de login state cookie.
+// Lax: de callback is een top-level redirect vanaf de universiteit.
+func setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, "/api/auth/oidc/", "", true, true)
+}
+
+// GetOIDCProviders - Universiteiten waarmee ingelogd kan worden (voor de loginpagina)
+func GetOIDCProviders(c *gin.Context) {
+	providers := []gin.H{}
+	for _, provider := range services.GetOIDCProviders() {
+		providers = append(providers, gin.H{
+			"id":         provider.ID,
+			"name":       provider.Name,
+			"university": provider.University,
+			"login_url":  "/api/auth/oidc/" + provider.ID + "/login",
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"providers": providers,
+	})
+}
+
+// OIDCLogin - Stuurt de gebruiker door naar de universiteit (authorization code + PKCE)
+func OIDCLogin(c *gin.Context) {
+	provider, err := services.GetOIDCProvider(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Unknown login provider",
+		})
+		return
+	}
+
+	authURL, stateToken, err := provider.AuthCodeURL()
+	if err != nil {
+		log.Printf("OIDC discovery failed for %s: %v", provider.ID, err)
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error": "Login provider unavailable",
+		})
+		return
+	}
+
+	setOIDCStateCookie(c, stateToken, int(services.OIDCStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback - Verwerkt de terugkeer van de universiteit en logt de gebruiker in
+func OIDCCallback(c *gin.Context) {
+	provider, err := services.GetOIDCProvider(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Unknown login provider",
+		})
+		return
+	}
+
+	if errorCode := c.Query("error"); errorCode != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Login cancelled or denied",
+			"details": errorCode,
+		})
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing code or state",
+		})
+		return
+	}
+
+	// State hoort bij deze browser; de cookie is eenmalig bruikbaar
+	stateToken, _ := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+
+	claims, err := provider.Exchange(code, state, stateToken)
+	if err != nil {
+		log.Printf("OIDC login via %s failed: %v", provider.ID, err)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Login failed",
+		})
+		return
+	}
+
+	user, err := services.LoginWithOIDC(provider, claims)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrEmailNotVerified) ||
+			errors.Is(err, services.ErrUserNotProvisioned) ||
+			errors.Is(err, services.ErrUserInactive) ||
+			errors.Is(err, services.ErrOIDCLinkNotAllowed) {
+			status = http.StatusForbidden
+		}
+		log.Printf("OIDC login via %s for %s rejected: %v", provider.ID, claims.Email, err)
+		c.JSON(status, gin.H{
+			"error":   "Login failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	token, err := services.GenerateToken(user.ID, user.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	// Lax: de callback is een cross-site redirect vanaf de universiteit
+	setAuthCookie(c, token, http.SameSiteLaxMode)
+	c.Redirect(http.StatusFound, frontendURL())
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/holidays"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type OpeningHoursRequest struct {
+	Weekday int    `json:"weekday" binding:"min=0,max=7"` // 0 = zondag, 7 = feestdag
+	Opens   string `json:"opens" binding:"required"`
+	Closes  string `json:"closes" binding:"required"`
+}
+
+type HoursExceptionRequest struct {
+	Date   string `json:"date" binding:"required"` // YYYY-MM-DD
+	Closed bool   `json:"closed"`
+	Opens  string `json:"opens"`
+	Closes string `json:"closes"`
+	Note   string `json:"note"`
+}
+
+// SetOpeningHours - Weekplanning van een bedrijf vervangen
+func SetOpeningHours(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	var req []OpeningHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	hours := make([]models.OpeningHours, 0, len(req))
+	for i, h := range req {
+		if h.Weekday < 0 || h.Weekday > models.HolidayWeekday || !services.ValidTimeOfDay(h.Opens) || !services.ValidTimeOfDay(h.Closes) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid opening hours",
+				"index": i,
+			})
+			return
+		}
+		hours = append(hours, models.OpeningHours{
+			BusinessID: business.ID,
+			Weekday:    h.Weekday,
+			Opens:      h.Opens,
+			Closes:     h.Closes,
+		})
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("business_id = ?", business.ID).Delete(&models.OpeningHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save opening hours",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"opening_hours": hours,
+		"message":       "Opening hours updated successfully",
+	})
+}
+
+// CreateHoursException - Afwijkende openingstijden op een datum (of gesloten)
+func CreateHoursException(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	var req HoursExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "date must be YYYY-MM-DD",
+		})
+		return
+	}
+
+	if !req.Closed && (!services.ValidTimeOfDay(req.Opens) || !services.ValidTimeOfDay(req.Closes)) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "opens and closes (HH:MM) are required unless closed",
+		})
+		return
+	}
+
+	exception := models.OpeningHoursException{
+		BusinessID: business.ID,
+		Date:       date,
+		Closed:     req.Closed,
+		Opens:      req.Opens,
+		Closes:     req.Closes,
+		Note:       req.Note,
+	}
+	if exception.Closed {
+		exception.Opens, exception.Closes = "", ""
+	}
+
+	if err := config.DB.Create(&exception).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create exception",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":   true,
+		"exception": exception,
+		"message":   "Exception created successfully",
+	})
+}
+
+// DeleteHoursException - Afwijkende openingstijden verwijderen
+func DeleteHoursException(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	exceptionID, ok := paramID(c, "exceptionId", "exception")
+	if !ok {
+		return
+	}
+
+	result := config.DB.Where("business_id = ?", business.ID).Delete(&models.OpeningHoursException{}, exceptionID)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete exception",
+			"details": result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Exception not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Exception deleted successfully",
+	})
+}
+
+// GetHolidays - Feestdagen in NRW en Niedersachsen (?year=, ?state=NW)
+func GetHolidays(c *gin.Context) {
+	year := time.Now().In(services.BerlinLocation).Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1900 || parsed > 2200 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid year",
+			})
+			return
+		}
+		year = parsed
+	}
+
+	state := c.Query("state")
+	list := []holidays.Holiday{}
+	for _, holiday := range holidays.ForYear(year) {
+		if state == "" || holiday.AppliesTo(state) {
+			list = append(list, holiday)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"year":     year,
+		"state":    state,
+		"holidays": list,
+	})
+}
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/images"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PhotoOrderRequest struct {
+	PhotoIDs []uint `json:"photo_ids" binding:"required,min=1"`
+}
+
+// maxUploadBytes - MAX_UPLOAD_MB (standaard 10 MB)
+func maxUploadBytes() int64 {
+	if mb, err := strconv.Atoi(os.Getenv("MAX_UPLOAD_MB")); err == nil && mb > 0 {
+		return int64(mb) << 20
+	}
+	return 10 << 20
+}
+
+// findPhoto haalt een foto van het bedrijf op
+func findPhoto(c *gin.Context, businessID uint) (*models.BusinessPhoto, bool) {
+	photoID, ok := paramID(c, "photoId", "photo")
+	if !ok {
+		return nil, false
+	}
+
+	var photo models.BusinessPhoto
+	if err := config.DB.Where("business_id = ?", businessID).First(&photo, photoID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Photo not found",
+			"id":    photoID,
+		})
+		return nil, false
+	}
+	return &photo, true
+}
+
+// GetBusinessPhotos - Foto's en logo van een bedrijf (admin, ook van inactieve bedrijven)
+func GetBusinessPhotos(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	photos := []models.BusinessPhoto{}
+	if err := config.DB.Where("business_id = ?", business.ID).Order("kind, sort_order, id").Find(&photos).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+	services.FillPhotoURLs(photos)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"photos":  photos,
+	})
+}
+
+// UploadBusinessPhoto - Foto of logo uploaden (multipart: file, kind=photo|logo, caption)
+func UploadBusinessPhoto(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	limit := maxUploadBytes()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+1<<20) // Ruimte voor de multipart velden
+
+	kind := c.DefaultPostForm("kind", models.PhotoKindPhoto)
+	if kind != models.PhotoKindPhoto && kind != models.PhotoKindLogo {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "kind must be photo or logo",
+		})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":     "File too large",
+				"max_bytes": limit,
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "file is required",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if header.Size > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":     "File too large",
+			"max_bytes": limit,
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to read file",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, limit))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to read file",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Type op basis van de inhoud, niet de meegestuurde Content-Type
+	if contentType, err := images.Sniff(data); err != nil {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error":        err.Error(),
+			"content_type": contentType,
+		})
+		return
+	}
+
+	photo, err := services.SavePhoto(c.Request.Context(), business.ID, kind, c.PostForm("caption"), data)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, images.ErrTooLarge) || errors.Is(err, images.ErrUnsupportedType) {
+			status = http.StatusUnprocessableEntity
+		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to process image",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"photo":   photo,
+		"message": "Photo uploaded successfully",
+	})
+}
+
+// ReorderBusinessPhotos - Volgorde van de foto's zetten (photo_ids in de nieuwe volgorde)
+func ReorderBusinessPhotos(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	var req PhotoOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&models.BusinessPhoto{}).Where("business_id = ? AND id IN ?", business.ID, req.PhotoIDs).Count(&count)
+	if int(count) != len(req.PhotoIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "photo_ids contains unknown or duplicate photos",
+		})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range req.PhotoIDs {
+			if err := tx.Model(&models.BusinessPhoto{}).Where("id = ?", id).UpdateColumn("sort_order", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to reorder photos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Photos reordered successfully",
+	})
+}
+
+// SetPrimaryPhoto - Foto als hoofdfoto instellen (thumbnail in lijsten)
+func SetPrimaryPhoto(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	photo, ok := findPhoto(c, business.ID)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.BusinessPhoto{}).
+			Where("business_id = ? AND kind = ?", business.ID, photo.Kind).
+			UpdateColumn("is_primary", false).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(photo).UpdateColumn("is_primary", true).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to set primary photo",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Primary photo updated successfully",
+	})
+}
+
+// DeleteBusinessPhoto - Foto verwijderen; de volgende foto wordt zo nodig hoofdfoto
+func DeleteBusinessPhoto(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	photo, ok := findPhoto(c, business.ID)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(photo).Error; err != nil {
+			return err
+		}
+		if !photo.IsPrimary {
+			return nil
+		}
+
+		var next models.BusinessPhoto
+		err := tx.Where("business_id = ? AND kind = ?", business.ID, photo.Kind).Order("sort_order, id").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).UpdateColumn("is_primary", true).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete photo",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	services.DeletePhotoFiles(c.Request.Context(), photo)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Photo deleted successfully",
+	})
+}
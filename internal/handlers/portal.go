@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentOwner - ingelogde eigenaar met gekoppelde klant; schrijft zelf een foutmelding
+func currentOwner(c *gin.Context) (*models.User, bool) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return nil, false
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil || !user.IsActive {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not found",
+		})
+		return nil, false
+	}
+	if user.CustomerID == nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Account is not linked to a customer",
+		})
+		return nil, false
+	}
+
+	return &user, true
+}
+
+// findOwnerBusiness - bedrijf van de klant van de eigenaar (anders 404, ook als het bestaat)
+func findOwnerBusiness(c *gin.Context, owner *models.User) (*models.Business, bool) {
+	id, ok := paramID(c, "id", "business")
+	if !ok {
+		return nil, false
+	}
+	var business models.Business
+
+	err := config.DB.Where("customer_id = ? AND merged_into_id IS NULL", *owner.CustomerID).
+		Preload("OpeningHours").
+		First(&business, id).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Business not found",
+			"id":    id,
+		})
+		return nil, false
+	}
+
+	return &business, true
+}
+
+// GetPortalBusinesses - Bedrijven van de eigen klant met openingstijden
+func GetPortalBusinesses(c *gin.Context) {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return
+	}
+
+	businesses := []models.Business{}
+	err := services.WithListingTier(config.DB).
+		Where("businesses.customer_id = ? AND businesses.merged_into_id IS NULL", *owner.CustomerID).
+		Preload("OpeningHours").
+		Preload("HoursExceptions", "date >= ?", time.Now().In(services.BerlinLocation).Format("2006-01-02")).
+		Order("name").
+		Find(&businesses).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"count":      len(businesses),
+		"businesses": businesses,
+	})
+}
+
+// GetPortalBusiness - Eén eigen bedrijf met de voorstellen
+func GetPortalBusiness(c *gin.Context) {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return
+	}
+	business, ok := findOwnerBusiness(c, owner)
+	if !ok {
+		return
+	}
+
+	proposals := []models.BusinessEditProposal{}
+	if err := config.DB.Where("business_id = ?", business.ID).Order("created_at DESC").Find(&proposals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"business":  business,
+		"proposals": proposals,
+		"fields":    services.ProposalFields,
+	})
+}
+
+// ProposalRequest - voorgestelde wijzigingen; opening_hours als "mon 09:00-18:00; tue 09:00-18:00"
+type ProposalRequest struct {
+	Changes map[string]string `json:"changes" binding:"required"`
+	Message string            `json:"message" binding:"max=1000"`
+}
+
+// CreatePortalProposal - Wijziging voorstellen; wordt pas zichtbaar na goedkeuring
+func CreatePortalProposal(c *gin.Context) {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return
+	}
+	business, ok := findOwnerBusiness(c, owner)
+	if !ok {
+		return
+	}
+
+	var req ProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	proposal, err := services.ProposeBusinessEdit(c.Request.Context(), business, owner.ID, req.Changes, req.Message)
+	switch {
+	case errors.Is(err, services.ErrUnknownProposalField):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   err.Error(),
+			"allowed": services.ProposalFields,
+		})
+		return
+	case errors.Is(err, services.ErrInvalidProposalValue), errors.Is(err, services.ErrInvalidOpeningHours),
+		errors.Is(err, services.ErrNoProposalChanges):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save proposal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"proposal": proposal,
+		"message":  "Proposal submitted for review",
+	})
+}
+
+// GetPortalProposals - Eigen voorstellen (?status=)
+func GetPortalProposals(c *gin.Context) {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Where("customer_id = ?", *owner.CustomerID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	proposals := []models.BusinessEditProposal{}
+	if err := query.Order("created_at DESC").Find(&proposals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"count":     len(proposals),
+		"proposals": proposals,
+	})
+}
+
+// WithdrawPortalProposal - Eigen voorstel intrekken zolang het nog niet behandeld is
+func WithdrawPortalProposal(c *gin.Context) {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := paramID(c, "id", "proposal")
+	if !ok {
+		return
+	}
+
+	proposal, err := services.WithdrawProposal(c.Request.Context(), id, *owner.CustomerID)
+	if err != nil {
+		writeProposalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"proposal": proposal,
+	})
+}
+
+// PortalInvoice - factuur zoals de klant hem ziet (zonder commissie van de student)
+type PortalInvoice struct {
+	ID            uint                 `json:"id"`
+	InvoiceNumber string               `json:"invoice_number"`
+	SubTotal      float64              `json:"subtotal"`
+	VATRate       float64              `json:"vat_rate"`
+	VATAmount     float64              `json:"vat_amount"`
+	Total         float64              `json:"total"`
+	InvoiceDate   time.Time            `json:"invoice_date"`
+	DueDate       time.Time            `json:"due_date"`
+	PaidDate      *time.Time           `json:"paid_date"`
+	Status        models.InvoiceStatus `json:"status"`
+	Description   string               `json:"description"`
+}
+
+// GetPortalInvoices - Facturen van de eigen klant (concepten niet)
+func GetPortalInvoices(c *gin.Context) {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return
+	}
+
+	var rows []models.Invoice
+	err := config.DB.Where("customer_id = ? AND status <> ?", *owner.CustomerID, models.InvoiceDraft).
+		Order("invoice_date DESC, id DESC").
+		Find(&rows).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	invoices := make([]PortalInvoice, len(rows))
+	var outstanding float64
+	for i, invoice := range rows {
+		invoices[i] = PortalInvoice{
+			ID:            invoice.ID,
+			InvoiceNumber: invoice.InvoiceNumber,
+			SubTotal:      invoice.SubTotal,
+			VATRate:       invoice.VATRate,
+			VATAmount:     invoice.VATAmount,
+			Total:         invoice.Total,
+			InvoiceDate:   invoice.InvoiceDate,
+			DueDate:       invoice.DueDate,
+			PaidDate:      invoice.PaidDate,
+			Status:        invoice.Status,
+			Description:   invoice.Description,
+		}
+		if invoice.Status == models.InvoiceSent || invoice.Status == models.InvoiceOverdue {
+			outstanding += invoice.Total
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"count":       len(invoices),
+		"outstanding": outstanding,
+		"invoices":    invoices,
+	})
+}
+
+// GetPortalReviews - Goedgekeurde reviews van de eigen bedrijven (?business_id=)
+func GetPortalReviews(c *gin.Context) {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return
+	}
+	limit, offset, ok := parseReviewLimit(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.Review{}).
+		Joins("JOIN businesses ON businesses.id = reviews.business_id").
+		Where("businesses.customer_id = ? AND reviews.status = ?", *owner.CustomerID, models.ReviewApproved)
+	if businessID := c.Query("business_id"); businessID != "" {
+		query = query.Where("reviews.business_id = ?", businessID)
+	}
+
+	reviews := []models.Review{}
+	err := query.Select("reviews.*").
+		Order("reviews.created_at DESC, reviews.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reviews).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+	services.PublicReviews(reviews)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(reviews),
+		"reviews": reviews,
+	})
+}
+
+// SetPortalReviewReply - Reactie van de eigenaar op een review van een eigen bedrijf
+func SetPortalReviewReply(c *gin.Context) {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return
+	}
+	id, ok := paramID(c, "id", "review")
+	if !ok {
+		return
+	}
+
+	var count int64
+	err := config.DB.Model(&models.Review{}).
+		Joins("JOIN businesses ON businesses.id = reviews.business_id").
+		Where("reviews.id = ? AND businesses.customer_id = ? AND reviews.status = ?", id, *owner.CustomerID, models.ReviewApproved).
+		Count(&count).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Review not found",
+			"id":    c.Param("id"),
+		})
+		return
+	}
+
+	SetReviewReply(c)
+}
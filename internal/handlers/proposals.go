@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxProposalLimit = 100
+
+// ProposalWithDiff - voorstel met per veld oud, huidig en nieuw
+type ProposalWithDiff struct {
+	models.BusinessEditProposal
+	Diff []services.ProposalChange `json:"diff"`
+}
+
+// writeProposalError - 404, 409 of 500
+func writeProposalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrProposalNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Proposal not found",
+			"id":    c.Param("id"),
+		})
+	case errors.Is(err, services.ErrProposalNotPending):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update proposal",
+			"details": err.Error(),
+		})
+	}
+}
+
+// withDiff - diff voor voorstellen met geladen Business (en OpeningHours)
+func withDiff(proposals []models.BusinessEditProposal) []ProposalWithDiff {
+	result := make([]ProposalWithDiff, len(proposals))
+	for i := range proposals {
+		result[i] = ProposalWithDiff{
+			BusinessEditProposal: proposals[i],
+			Diff:                 services.ProposalDiff(&proposals[i]),
+		}
+	}
+	return result
+}
+
+// GetProposals - Wachtrij met wijzigingsvoorstellen van eigenaren (?status=pending, ?business_id=, ?limit=)
+func GetProposals(c *gin.Context) {
+	query := config.DB.Model(&models.BusinessEditProposal{})
+
+	switch status := c.DefaultQuery("status", models.ProposalPending); status {
+	case "all":
+	case models.ProposalPending, models.ProposalApproved, models.ProposalRejected, models.ProposalWithdrawn:
+		query = query.Where("status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "status must be pending, approved, rejected, withdrawn or all",
+		})
+		return
+	}
+	if businessID := c.Query("business_id"); businessID != "" {
+		query = query.Where("business_id = ?", businessID)
+	}
+
+	limit := maxProposalLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxProposalLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be between 1 and " + strconv.Itoa(maxProposalLimit),
+			})
+			return
+		}
+		limit = n
+	}
+
+	proposals := []models.BusinessEditProposal{}
+	err := query.Preload("Business.OpeningHours").
+		Preload("ProposedBy").
+		Order("created_at, id"). // Oudste eerst
+		Limit(limit).
+		Find(&proposals).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"count":     len(proposals),
+		"proposals": withDiff(proposals),
+	})
+}
+
+// GetProposal - Eén voorstel met diff
+func GetProposal(c *gin.Context) {
+	id, ok := paramID(c, "id", "proposal")
+	if !ok {
+		return
+	}
+
+	var proposal models.BusinessEditProposal
+	if err := config.DB.Preload("Business.OpeningHours").Preload("ProposedBy").First(&proposal, id).Error; err != nil {
+		writeProposalError(c, services.ErrProposalNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"proposal": withDiff([]models.BusinessEditProposal{proposal})[0],
+	})
+}
+
+// reviewProposal - gedeelde afhandeling van goedkeuren en afwijzen ({"note": "..."})
+func reviewProposal(c *gin.Context, approve bool) {
+	id, ok := paramID(c, "id", "proposal")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Note string `json:"note" binding:"max=1000"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid JSON data",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	var proposal *models.BusinessEditProposal
+	var err error
+	if approve {
+		proposal, err = services.ApproveProposal(c.Request.Context(), id, moderatorID(c), strings.TrimSpace(req.Note))
+	} else {
+		proposal, err = services.RejectProposal(c.Request.Context(), id, moderatorID(c), strings.TrimSpace(req.Note))
+	}
+	if err != nil {
+		writeProposalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"proposal": proposal,
+	})
+}
+
+// ApproveProposal - Voorstel doorvoeren op het bedrijf
+func ApproveProposal(c *gin.Context) {
+	reviewProposal(c, true)
+}
+
+// RejectProposal - Voorstel afwijzen (met toelichting voor de eigenaar)
+func RejectProposal(c *gin.Context) {
+	reviewProposal(c, false)
+}
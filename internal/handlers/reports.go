@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamReport - geaggregeerde cijfers per team
+type TeamReport struct {
+	TeamID                uint    `json:"team_id"`
+	Name                  string  `json:"name"`
+	University            string  `json:"university"`
+	Cohort                string  `json:"cohort"`
+	Members               int     `json:"members"`
+	Customers             int64   `json:"customers"`
+	ActiveCustomers       int64   `json:"active_customers"`
+	MonthlyRevenue        float64 `json:"monthly_revenue"` // Som van maandbedragen van actieve klanten
+	CommissionPaid        float64 `json:"commission_paid"`
+	CommissionOutstanding float64 `json:"commission_outstanding"`
+}
+
+// userCustomerStats - klantcijfers per student
+type userCustomerStats struct {
+	UserID          uint
+	Customers       int64
+	ActiveCustomers int64
+	MonthlyRevenue  float64
+}
+
+// userCommissionStats - commissie per student
+type userCommissionStats struct {
+	UserID      uint
+	Paid        float64
+	Outstanding float64
+}
+
+// GetTeamReports - Rapportage per team (admin: alle teams, supervisor: eigen teams)
+func GetTeamReports(c *gin.Context) {
+	visibility, ok := currentVisibility(c)
+	if !ok {
+		return
+	}
+	userID, _, _ := currentUser(c)
+
+	var teams []models.Team
+	query := config.DB.Preload("Members").Order("name")
+	if !visibility.All {
+		query = query.Where("supervisor_id = ?", userID)
+	}
+	if err := query.Find(&teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var customerStats []userCustomerStats
+	if err := config.DB.Model(&models.Customer{}).
+		Select(`acquired_by_user_id AS user_id,
+			COUNT(*) AS customers,
+			COUNT(*) FILTER (WHERE status = ?) AS active_customers,
+			COALESCE(SUM(monthly_fee) FILTER (WHERE status = ?), 0) AS monthly_revenue`,
+			models.StatusActive, models.StatusActive).
+		Group("acquired_by_user_id").
+		Scan(&customerStats).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var commissionStats []userCommissionStats
+	if err := config.DB.Model(&models.Invoice{}).
+		Select(`commission_user_id AS user_id,
+			COALESCE(SUM(commission_amount) FILTER (WHERE status = ?), 0) AS paid,
+			COALESCE(SUM(commission_amount) FILTER (WHERE status IN ?), 0) AS outstanding`,
+			models.InvoicePaid, []models.InvoiceStatus{models.InvoiceSent, models.InvoiceOverdue}).
+		Group("commission_user_id").
+		Scan(&commissionStats).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	customersByUser := make(map[uint]userCustomerStats)
+	for _, s := range customerStats {
+		customersByUser[s.UserID] = s
+	}
+	commissionByUser := make(map[uint]userCommissionStats)
+	for _, s := range commissionStats {
+		commissionByUser[s.UserID] = s
+	}
+
+	reports := make([]TeamReport, 0, len(teams))
+	for _, team := range teams {
+		report := TeamReport{
+			TeamID:     team.ID,
+			Name:       team.Name,
+			University: team.University,
+			Cohort:     team.Cohort,
+			Members:    len(team.Members),
+		}
+
+		for _, member := range team.Members {
+			customers := customersByUser[member.ID]
+			report.Customers += customers.Customers
+			report.ActiveCustomers += customers.ActiveCustomers
+			report.MonthlyRevenue += customers.MonthlyRevenue
+
+			commission := commissionByUser[member.ID]
+			report.CommissionPaid += commission.Paid
+			report.CommissionOutstanding += commission.Outstanding
+		}
+
+		reports = append(reports, report)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(reports),
+		"teams":   reports,
+	})
+}
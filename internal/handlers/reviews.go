@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultReviewLimit = 20
+	maxReviewLimit     = 100
+	maxReviewAgeDays   = 2 * 365 // Bezoeken van langer geleden zeggen weinig meer
+)
+
+// ReviewRequest - review van een bezoeker
+type ReviewRequest struct {
+	Rating     int    `json:"rating" binding:"required,min=1,max=5"`
+	Text       string `json:"text" binding:"max=5000"`
+	Language   string `json:"language" binding:"omitempty,len=2"` // Leeg: taal van het request
+	VisitDate  string `json:"visit_date"`                         // YYYY-MM-DD
+	AuthorName string `json:"author_name" binding:"max=80"`       // Verplicht voor anonieme bezoekers
+	Website    string `json:"website"`                            // Honeypot: blijft leeg in het formulier
+}
+
+// parseReviewLimit - ?limit= en ?offset= voor reviewlijsten
+func parseReviewLimit(c *gin.Context) (int, int, bool) {
+	limit := defaultReviewLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxReviewLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be between 1 and " + strconv.Itoa(maxReviewLimit),
+			})
+			return 0, 0, false
+		}
+		limit = n
+	}
+
+	offset := 0
+	if value := c.Query("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "offset must be 0 or more",
+			})
+			return 0, 0, false
+		}
+		offset = n
+	}
+
+	return limit, offset, true
+}
+
+// GetBusinessReviews - Goedgekeurde reviews van een bedrijf (?language=, limit, offset)
+func GetBusinessReviews(c *gin.Context) {
+	id, ok := paramID(c, "id", "business")
+	if !ok {
+		return
+	}
+	var business models.Business
+	if err := config.DB.Select("id", "rating_average", "rating_count").Where("is_active = ?", true).First(&business, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Business not found",
+			"id":    id,
+		})
+		return
+	}
+
+	limit, offset, ok := parseReviewLimit(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.Review{}).Where("business_id = ? AND status = ?", business.ID, models.ReviewApproved)
+	if language := c.Query("language"); language != "" {
+		query = query.Where("language = ?", language)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	reviews := []models.Review{}
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&reviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+	services.PublicReviews(reviews)
+
+	// Verdeling over 1 t/m 5 sterren (alle talen)
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+	err := config.DB.Model(&models.Review{}).
+		Select("rating, COUNT(*) AS count").
+		Where("business_id = ? AND status = ?", business.ID, models.ReviewApproved).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+	distribution := map[string]int64{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0}
+	for _, row := range rows {
+		distribution[strconv.Itoa(row.Rating)] = row.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"business_id":    business.ID,
+		"rating_average": business.RatingAverage,
+		"rating_count":   business.RatingCount,
+		"distribution":   distribution,
+		"total":          total,
+		"count":          len(reviews),
+		"reviews":        reviews,
+	})
+}
+
+// CreateReview - Review plaatsen (anoniem of ingelogd). Komt altijd eerst in de
+// moderatie wachtrij; verdachte reviews krijgen status spam.
+func CreateReview(c *gin.Context) {
+	id, ok := paramID(c, "id", "business")
+	if !ok {
+		return
+	}
+	var business models.Business
+	if err := config.DB.Select("id").Where("is_active = ?", true).First(&business, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Business not found",
+			"id":    id,
+		})
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	input := services.ReviewInput{
+		BusinessID: business.ID,
+		AuthorName: strings.TrimSpace(req.AuthorName),
+		Rating:     req.Rating,
+		Text:       req.Text,
+		Language:   strings.ToLower(req.Language),
+		IP:         c.ClientIP(),
+		Honeypot:   req.Website,
+	}
+
+	if input.Language == "" {
+		input.Language = requestLanguage(c)
+	} else if !services.IsSupportedLanguage(input.Language) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     "Unsupported language",
+			"supported": services.SupportedLanguages,
+		})
+		return
+	}
+
+	if req.VisitDate != "" {
+		visitDate, err := time.ParseInLocation("2006-01-02", req.VisitDate, services.BerlinLocation)
+		today := time.Now().In(services.BerlinLocation)
+		if err != nil || visitDate.After(today) || visitDate.Before(today.AddDate(0, 0, -maxReviewAgeDays)) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "visit_date must be a date (YYYY-MM-DD) within the last two years, not in the future",
+			})
+			return
+		}
+		input.VisitDate = &visitDate
+	}
+
+	// Ingelogde gebruikers: naam uit het profiel (voornaam en eerste letter achternaam)
+	if userID, _, ok := currentUser(c); ok {
+		var user models.User
+		if err := config.DB.First(&user, userID).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
+		input.UserID = &userID
+		if input.AuthorName == "" {
+			input.AuthorName = user.FirstName
+			if initial, _ := utf8.DecodeRuneInString(user.LastName); initial != utf8.RuneError {
+				input.AuthorName += " " + string(initial) + "."
+			}
+		}
+	}
+
+	if input.AuthorName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "author_name is required",
+		})
+		return
+	}
+
+	review, err := services.SubmitReview(c.Request.Context(), input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save review",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Ook bij spam "pending": spammers hoeven niet te weten dat ze zijn herkend
+	c.JSON(http.StatusAccepted, gin.H{
+		"success":   true,
+		"review_id": review.ID,
+		"status":    models.ReviewPending,
+		"message":   "Review received and awaiting moderation",
+	})
+}
+
+// GetReviewQueue - Moderatie wachtrij (?status=pending|spam|approved|rejected|all, ?business_id=)
+func GetReviewQueue(c *gin.Context) {
+	limit, offset, ok := parseReviewLimit(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.Review{})
+	switch status := c.DefaultQuery("status", models.ReviewPending); status {
+	case "all":
+	case models.ReviewPending, models.ReviewSpam, models.ReviewApproved, models.ReviewRejected:
+		query = query.Where("status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "status must be pending, spam, approved, rejected or all",
+		})
+		return
+	}
+	if businessID := c.Query("business_id"); businessID != "" {
+		query = query.Where("business_id = ?", businessID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	reviews := []models.Review{}
+	err := query.Preload("Business").
+		Order("created_at, id"). // Oudste eerst
+		Limit(limit).
+		Offset(offset).
+		Find(&reviews).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"total":   total,
+		"count":   len(reviews),
+		"reviews": reviews,
+	})
+}
+
+// moderatorID - ingelogde gebruiker (leeg bij API keys)
+func moderatorID(c *gin.Context) *uint {
+	if userID, _, ok := currentUser(c); ok {
+		return &userID
+	}
+	return nil
+}
+
+// writeReviewError - 404 voor onbekende reviews, anders 500
+func writeReviewError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrReviewNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Review not found",
+			"id":    c.Param("id"),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Failed to update review",
+		"details": err.Error(),
+	})
+}
+
+// moderateReview - gedeelde afhandeling van goedkeuren en afwijzen
+func moderateReview(c *gin.Context, status string) {
+	id, ok := paramID(c, "id", "review")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Note string `json:"note" binding:"max=500"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid JSON data",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	review, err := services.ModerateReview(c.Request.Context(), id, status, moderatorID(c), strings.TrimSpace(req.Note))
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"review":  review,
+	})
+}
+
+// ApproveReview - Review publiceren
+func ApproveReview(c *gin.Context) {
+	moderateReview(c, models.ReviewApproved)
+}
+
+// RejectReview - Review afwijzen (optioneel {"note": "..."})
+func RejectReview(c *gin.Context) {
+	moderateReview(c, models.ReviewRejected)
+}
+
+// MarkReviewSpam - Review als spam markeren
+func MarkReviewSpam(c *gin.Context) {
+	moderateReview(c, models.ReviewSpam)
+}
+
+// SetReviewReply - Publieke reactie van het bedrijf; een lege reply verwijdert de reactie
+func SetReviewReply(c *gin.Context) {
+	id, ok := paramID(c, "id", "review")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reply string `json:"reply" binding:"max=2000"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	review, err := services.SetReviewReply(c.Request.Context(), id, req.Reply, moderatorID(c))
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"review":  review,
+	})
+}
+
+// DeleteReview - Review definitief verwijderen
+func DeleteReview(c *gin.Context) {
+	id, ok := paramID(c, "id", "review")
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteReview(c.Request.Context(), id); err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Review deleted successfully",
+	})
+}
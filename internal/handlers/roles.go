@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleRequest struct {
+	Name        models.Role         `json:"name"`
+	Description string              `json:"description"`
+	Permissions []models.Permission `json:"permissions" binding:"required"`
+}
+
+// validatePermissions zet permissies om naar een StringList en controleert ze
+func validatePermissions(permissions []models.Permission) (models.StringList, []models.Permission) {
+	var list models.StringList
+	var invalid []models.Permission
+
+	for _, p := range permissions {
+		if !models.IsValidPermission(p) {
+			invalid = append(invalid, p)
+			continue
+		}
+		if !list.Contains(string(p)) {
+			list = append(list, string(p))
+		}
+	}
+
+	return list, invalid
+}
+
+// GetPermissions - Lijst van alle bekende permissies
+func GetPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"permissions": models.AllPermissions,
+	})
+}
+
+// GetRoles - Alle rollen met permissies
+func GetRoles(c *gin.Context) {
+	var roles []models.RoleDefinition
+
+	if err := config.DB.Order("name").Find(&roles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(roles),
+		"roles":   roles,
+	})
+}
+
+// CreateRole - Nieuwe rol aanmaken
+func CreateRole(c *gin.Context) {
+	var req RoleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil || req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request data",
+		})
+		return
+	}
+
+	permissions, invalid := validatePermissions(req.Permissions)
+	if len(invalid) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unknown permissions",
+			"invalid": invalid,
+		})
+		return
+	}
+
+	var existing models.RoleDefinition
+	if config.DB.Where("name = ?", req.Name).First(&existing).Error == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Role already exists",
+		})
+		return
+	}
+
+	role := models.RoleDefinition{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+
+	if err := config.DB.Create(&role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create role",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	services.InvalidateRoleCache()
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"role":    role,
+		"message": "Role created successfully",
+	})
+}
+
+// UpdateRole - Omschrijving en permissies van een rol wijzigen
+func UpdateRole(c *gin.Context) {
+	id, ok := paramID(c, "id", "role")
+	if !ok {
+		return
+	}
+	var role models.RoleDefinition
+
+	if err := config.DB.First(&role, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Role not found",
+			"id":    id,
+		})
+		return
+	}
+
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Admin moet altijd volledige toegang houden, anders sluit je jezelf buiten
+	if role.Name == models.RoleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "The admin role cannot be modified",
+		})
+		return
+	}
+
+	permissions, invalid := validatePermissions(req.Permissions)
+	if len(invalid) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unknown permissions",
+			"invalid": invalid,
+		})
+		return
+	}
+
+	role.Permissions = permissions
+	if req.Description != "" {
+		role.Description = req.Description
+	}
+
+	if err := config.DB.Save(&role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update role",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	services.InvalidateRoleCache()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"role":    role,
+		"message": "Role updated successfully",
+	})
+}
+
+// DeleteRole - Rol verwijderen (niet voor ingebouwde of gebruikte rollen)
+func DeleteRole(c *gin.Context) {
+	id, ok := paramID(c, "id", "role")
+	if !ok {
+		return
+	}
+	var role models.RoleDefinition
+
+	if err := config.DB.First(&role, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Role not found",
+			"id":    id,
+		})
+		return
+	}
+
+	if role.BuiltIn {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Built-in roles cannot be deleted",
+		})
+		return
+	}
+
+	var userCount int64
+	config.DB.Model(&models.User{}).Where("role = ?", role.Name).Count(&userCount)
+	if userCount > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Role is still assigned to users",
+			"users": userCount,
+		})
+		return
+	}
+
+	if err := config.DB.Delete(&role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete role",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	services.InvalidateRoleCache()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role deleted successfully",
+	})
+}
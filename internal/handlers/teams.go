@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TeamRequest struct {
+	Name         string `json:"name" binding:"required"`
+	University   string `json:"university"`
+	Cohort       string `json:"cohort"`
+	SupervisorID *uint  `json:"supervisor_id"`
+}
+
+type TeamMembersRequest struct {
+	UserIDs []uint `json:"user_ids" binding:"required"`
+}
+
+// validateSupervisor controleert of de supervisor bestaat en teamdata mag inzien
+func validateSupervisor(c *gin.Context, supervisorID *uint) bool {
+	if supervisorID == nil {
+		return true
+	}
+
+	var supervisor models.User
+	if err := config.DB.First(&supervisor, *supervisorID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Supervisor not found",
+			"id":    *supervisorID,
+		})
+		return false
+	}
+
+	if !services.HasPermission(supervisor.Role, models.PermTeamRead) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Supervisor role lacks team:read permission",
+			"role":  supervisor.Role,
+		})
+		return false
+	}
+
+	return true
+}
+
+// GetTeams - Alle teams met supervisor en leden
+func GetTeams(c *gin.Context) {
+	var teams []models.Team
+
+	query := config.DB.Preload("Supervisor").Preload("Members").Order("name")
+
+	if university := c.Query("university"); university != "" {
+		query = query.Where("university = ?", university)
+	}
+
+	if err := query.Find(&teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(teams),
+		"teams":   teams,
+	})
+}
+
+// CreateTeam - Nieuw team aanmaken
+func CreateTeam(c *gin.Context) {
+	var req TeamRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !validateSupervisor(c, req.SupervisorID) {
+		return
+	}
+
+	team := models.Team{
+		Name:         req.Name,
+		University:   req.University,
+		Cohort:       req.Cohort,
+		SupervisorID: req.SupervisorID,
+	}
+
+	if err := config.DB.Create(&team).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create team",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"team":    team,
+		"message": "Team created successfully",
+	})
+}
+
+// UpdateTeam - Team gegevens en supervisor wijzigen
+func UpdateTeam(c *gin.Context) {
+	id, ok := paramID(c, "id", "team")
+	if !ok {
+		return
+	}
+	var team models.Team
+
+	if err := config.DB.First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Team not found",
+			"id":    id,
+		})
+		return
+	}
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !validateSupervisor(c, req.SupervisorID) {
+		return
+	}
+
+	team.Name = req.Name
+	team.University = req.University
+	team.Cohort = req.Cohort
+	team.SupervisorID = req.SupervisorID
+
+	if err := config.DB.Save(&team).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update team",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"team":    team,
+		"message": "Team updated successfully",
+	})
+}
+
+// DeleteTeam - Team verwijderen (leden blijven bestaan)
+func DeleteTeam(c *gin.Context) {
+	id, ok := paramID(c, "id", "team")
+	if !ok {
+		return
+	}
+	var team models.Team
+
+	if err := config.DB.First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Team not found",
+			"id":    id,
+		})
+		return
+	}
+
+	if err := config.DB.Model(&team).Association("Members").Clear(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to remove team members",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := config.DB.Delete(&team).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete team",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Team deleted successfully",
+	})
+}
+
+// AddTeamMembers - Gebruikers aan een team toevoegen
+func AddTeamMembers(c *gin.Context) {
+	id, ok := paramID(c, "id", "team")
+	if !ok {
+		return
+	}
+	var team models.Team
+
+	if err := config.DB.First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Team not found",
+			"id":    id,
+		})
+		return
+	}
+
+	var req TeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var users []models.User
+	if err := config.DB.Where("id IN ?", req.UserIDs).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if len(users) != len(req.UserIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "One or more users not found",
+		})
+		return
+	}
+
+	if err := config.DB.Model(&team).Association("Members").Append(&users); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to add team members",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	config.DB.Preload("Members").First(&team, team.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"team":    team,
+		"message": "Team members added successfully",
+	})
+}
+
+// RemoveTeamMember - Gebruiker uit een team halen
+func RemoveTeamMember(c *gin.Context) {
+	id, ok := paramID(c, "id", "team")
+	if !ok {
+		return
+	}
+	var team models.Team
+
+	if err := config.DB.First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Team not found",
+			"id":    id,
+		})
+		return
+	}
+
+	userID, ok := paramID(c, "userId", "user")
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	}
+
+	if err := config.DB.Model(&team).Association("Members").Delete(&user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to remove team member",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Team member removed successfully",
+	})
+}
+
+// GetMyTeams - Teams die de ingelogde gebruiker begeleidt
+func GetMyTeams(c *gin.Context) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	var teams []models.Team
+	if err := config.DB.Preload("Members").Where("supervisor_id = ?", userID).Find(&teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"count":   len(teams),
+		"teams":   teams,
+	})
+}
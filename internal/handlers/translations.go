@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"net/http"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+type BusinessTranslationRequest struct {
+	Description string `json:"description" binding:"required"`
+}
+
+type CategoryTranslationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// translationLanguage leest :lang en controleert of de taal ondersteund wordt
+func translationLanguage(c *gin.Context) (string, bool) {
+	lang := strings.ToLower(c.Param("lang"))
+	if !services.IsSupportedLanguage(lang) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     "Unsupported language",
+			"supported": services.SupportedLanguages,
+		})
+		return "", false
+	}
+	return lang, true
+}
+
+// GetBusinessTranslations - Alle vertalingen van een bedrijf
+func GetBusinessTranslations(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	translations := []models.BusinessTranslation{}
+	if err := config.DB.Where("business_id = ?", business.ID).Order("language").Find(&translations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"translations": translations,
+	})
+}
+
+// SetBusinessTranslation - Omschrijving in één taal aanmaken of bijwerken
+func SetBusinessTranslation(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	lang, ok := translationLanguage(c)
+	if !ok {
+		return
+	}
+
+	var req BusinessTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	translation := models.BusinessTranslation{
+		BusinessID:  business.ID,
+		Language:    lang,
+		Description: req.Description,
+	}
+
+	err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "business_id"}, {Name: "language"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "updated_at"}),
+	}).Create(&translation).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save translation",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"translation": translation,
+		"message":     "Translation saved successfully",
+	})
+}
+
+// DeleteBusinessTranslation - Vertaling verwijderen (valt terug op andere talen)
+func DeleteBusinessTranslation(c *gin.Context) {
+	business, ok := findBusiness(c)
+	if !ok {
+		return
+	}
+
+	lang, ok := translationLanguage(c)
+	if !ok {
+		return
+	}
+
+	result := config.DB.Where("business_id = ? AND language = ?", business.ID, lang).Delete(&models.BusinessTranslation{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete translation",
+			"details": result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Translation not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Translation deleted successfully",
+	})
+}
+
+// GetCategoryTranslations - Vertaalde categorienamen (?slug= optioneel)
+func GetCategoryTranslations(c *gin.Context) {
+	query := config.DB.Order("slug, language")
+	if slug := c.Query("slug"); slug != "" {
+		query = query.Where("slug = ?", slug)
+	}
+
+	translations := []models.CategoryTranslation{}
+	if err := query.Find(&translations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"translations": translations,
+	})
+}
+
+// SetCategoryTranslation - Naam van een categorie in één taal aanmaken of bijwerken
+func SetCategoryTranslation(c *gin.Context) {
+	lang, ok := translationLanguage(c)
+	if !ok {
+		return
+	}
+
+	var req CategoryTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	translation := models.CategoryTranslation{
+		Slug:     c.Param("slug"),
+		Language: lang,
+		Name:     strings.TrimSpace(req.Name),
+	}
+
+	err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}, {Name: "language"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).Create(&translation).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save translation",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"translation": translation,
+		"message":     "Translation saved successfully",
+	})
+}
+
+// DeleteCategoryTranslation - Categorienaam in één taal verwijderen
+func DeleteCategoryTranslation(c *gin.Context) {
+	lang, ok := translationLanguage(c)
+	if !ok {
+		return
+	}
+
+	result := config.DB.Where("slug = ? AND language = ?", c.Param("slug"), lang).Delete(&models.CategoryTranslation{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete translation",
+			"details": result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Translation not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Translation deleted successfully",
+	})
+}
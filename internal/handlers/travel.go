@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"projectpeterperplexity/internal/border"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBusinessTravel - Afstand, geschatte reistijd en dichtstbijzijnde grensovergang
+// vanaf een Nederlandse postcode of coördinaat (?from=7511AB of ?from=52.22,6.89)
+func GetBusinessTravel(c *gin.Context) {
+	from := c.Query("from")
+	if from == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "from is required (Dutch postal code or lat,lng)",
+		})
+		return
+	}
+
+	origin, err := services.ResolveOrigin(c.Request.Context(), from)
+	if err != nil {
+		writeOriginError(c, err)
+		return
+	}
+
+	id, ok := paramID(c, "id", "business")
+	if !ok {
+		return
+	}
+	var business models.Business
+	if err := config.DB.Where("is_active = ?", true).First(&business, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Business not found",
+			"id":    id,
+		})
+		return
+	}
+
+	travel := services.EstimateTravel(origin.Point, geo.Point{Lat: business.Latitude, Lng: business.Longitude})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"business_id": business.ID,
+		"from":        origin,
+		"travel":      travel,
+	})
+}
+
+// writeOriginError - 503 zonder postcodebestand op de server, 404 bij een onbekende postcode, anders 400
+func writeOriginError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNoPostcodeData):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Postal code lookup unavailable",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrUnknownPostcode):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Invalid from",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid from",
+			"details": err.Error(),
+		})
+	}
+}
+
+// GetBorderCrossings - De grensovergangen waarmee afstanden tot de grens berekend worden
+func GetBorderCrossings(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"count":     len(border.Crossings),
+		"crossings": border.Crossings,
+	})
+}
@@ -0,0 +1,178 @@
+package holidays
+
+import (
+	"projectpeterperplexity/internal/geocoding"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bundesland codes (ISO 3166-2:DE zonder "DE-")
+const (
+	StateNRW           = "NW" // Nordrhein-Westfalen
+	StateNiedersachsen = "NI" // Niedersachsen
+)
+
+// SupportedStates - deelstaten waarvan we de feestdagen kennen
+var SupportedStates = []string{StateNRW, StateNiedersachsen}
+
+// Holiday - wettelijke feestdag
+type Holiday struct {
+	Date   string   `json:"date"` // YYYY-MM-DD
+	Name   string   `json:"name"`
+	States []string `json:"states"` // Leeg = landelijk
+}
+
+// Nationwide - geldt in heel Duitsland
+func (h Holiday) Nationwide() bool {
+	return len(h.States) == 0
+}
+
+// AppliesTo controleert of de feestdag in een deelstaat geldt
+func (h Holiday) AppliesTo(state string) bool {
+	if h.Nationwide() {
+		return true
+	}
+	for _, s := range h.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Easter berekent eerste paasdag (Gregoriaanse kalender, anonymous algorithm)
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := ((h + l - 7*m + 114) % 31) + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// ForYear geeft alle feestdagen van NRW en Niedersachsen in een jaar, offline berekend
+func ForYear(year int) []Holiday {
+	easter := Easter(year)
+	fixed := func(month time.Month, day int) string {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	}
+	relative := func(days int) string {
+		return easter.AddDate(0, 0, days).Format("2006-01-02")
+	}
+
+	list := []Holiday{
+		{Date: fixed(time.January, 1), Name: "Neujahr"},
+		{Date: relative(-2), Name: "Karfreitag"},
+		{Date: relative(1), Name: "Ostermontag"},
+		{Date: fixed(time.May, 1), Name: "Tag der Arbeit"},
+		{Date: relative(39), Name: "Christi Himmelfahrt"},
+		{Date: relative(50), Name: "Pfingstmontag"},
+		{Date: relative(60), Name: "Fronleichnam", States: []string{StateNRW}},
+		{Date: fixed(time.October, 3), Name: "Tag der Deutschen Einheit"},
+		{Date: fixed(time.October, 31), Name: "Reformationstag", States: []string{StateNiedersachsen}},
+		{Date: fixed(time.November, 1), Name: "Allerheiligen", States: []string{StateNRW}},
+		{Date: fixed(time.December, 25), Name: "1. Weihnachtstag"},
+		{Date: fixed(time.December, 26), Name: "2. Weihnachtstag"},
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+	return list
+}
+
+// On geeft de feestdag op een datum (YYYY-MM-DD), of nil
+func On(date time.Time) *Holiday {
+	day := date.Format("2006-01-02")
+	for _, holiday := range ForYear(date.Year()) {
+		if holiday.Date == day {
+			h := holiday
+			return &h
+		}
+	}
+	return nil
+}
+
+// IsHoliday controleert of een datum in een deelstaat een feestdag is.
+// Zonder (bekende) deelstaat tellen alleen landelijke feestdagen.
+func IsHoliday(date time.Time, state string) bool {
+	holiday := On(date)
+	return holiday != nil && holiday.AppliesTo(state)
+}
+
+// postalRange - aaneengesloten reeks Duitse postcodes binnen één deelstaat
+type postalRange struct {
+	From, To int
+	State    string
+}
+
+// postalRanges - de grensregio met Nederland. De Leitregionen 27, 29, 38, 48, 49,
+// 51, 53 en 57 lopen over deelstaatgrenzen heen (bv. Nordhorn 48527 NI, Rheine 48431 NW,
+// Ibbenbüren 49477 NW); die zijn daarom op vijf cijfers uitgesplitst. Wat er niet in
+// staat (Bremen, Sachsen-Anhalt, Rheinland-Pfalz, verder weg) geeft "".
+var postalRanges = []postalRange{
+	{26000, 26999, StateNiedersachsen},
+	{27000, 27567, StateNiedersachsen}, // 27568-27580 Bremerhaven
+	{27581, 27999, StateNiedersachsen},
+	{28780, 28999, StateNiedersachsen}, // Daaronder de stad Bremen
+	{29000, 29409, StateNiedersachsen}, // 29410-29416 Salzwedel (Sachsen-Anhalt)
+	{29417, 29999, StateNiedersachsen},
+	{30000, 31999, StateNiedersachsen},
+	{32000, 33999, StateNRW},
+	{38000, 38479, StateNiedersachsen}, // 38486-38489 en 38820+ Sachsen-Anhalt
+	{38500, 38729, StateNiedersachsen},
+	{40000, 48432, StateNRW},
+	{48455, 48465, StateNiedersachsen}, // Bad Bentheim, Schüttorf
+	{48477, 48477, StateNRW},           // Hörstel
+	{48480, 48480, StateNiedersachsen}, // Spelle
+	{48485, 48485, StateNRW},           // Neuenkirchen
+	{48488, 48488, StateNiedersachsen}, // Emsbüren
+	{48493, 48496, StateNRW},           // Wettringen, Hopsten
+	{48499, 48531, StateNiedersachsen}, // Salzbergen, Nordhorn
+	{48540, 48739, StateNRW},           // Steinfurt, Gronau, Ahaus
+	{49000, 49476, StateNiedersachsen},
+	{49477, 49549, StateNRW}, // Tecklenburger Land
+	{49550, 49999, StateNiedersachsen},
+	{50000, 51597, StateNRW}, // 51598 Friesenhagen (Rheinland-Pfalz)
+	{51599, 52999, StateNRW},
+	{53000, 53359, StateNRW}, // 53401-53579 en 53619 Rheinland-Pfalz
+	{53604, 53604, StateNRW},
+	{53620, 53949, StateNRW},
+	{57000, 57489, StateNRW}, // Daarboven Westerwald (Rheinland-Pfalz)
+	{58000, 59999, StateNRW},
+}
+
+// StateForPostalCode - deelstaat van een Duits adres op basis van de postcode.
+// "" voor adressen buiten Duitsland (bv. Venlo 5911), voor alles wat geen vijfcijferige
+// postcode is en voor postcodes buiten de bekende reeksen.
+func StateForPostalCode(country, postalCode string) string {
+	if geocoding.CountryCode(country) != "DE" {
+		return ""
+	}
+
+	postalCode = strings.TrimSpace(postalCode)
+	if len(postalCode) != 5 {
+		return ""
+	}
+	code, err := strconv.Atoi(postalCode)
+	if err != nil || code < 0 {
+		return ""
+	}
+
+	for _, r := range postalRanges {
+		if code >= r.From && code <= r.To {
+			return r.State
+		}
+	}
+	return ""
+}
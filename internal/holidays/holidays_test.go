@@ -0,0 +1,72 @@
+package holidays
+
+import "testing"
+
+func TestStateForPostalCode(t *testing.T) {
+	tests := []struct {
+		place      string
+		country    string
+		postalCode string
+		want       string
+	}{
+		// Grens NRW / Niedersachsen
+		{"Münster", "Germany", "48143", StateNRW},
+		{"Rheine", "Germany", "48431", StateNRW},
+		{"Bad Bentheim", "Germany", "48455", StateNiedersachsen},
+		{"Schüttorf", "Germany", "48465", StateNiedersachsen},
+		{"Hörstel", "Germany", "48477", StateNRW},
+		{"Emsbüren", "Germany", "48488", StateNiedersachsen},
+		{"Nordhorn", "Germany", "48527", StateNiedersachsen},
+		{"Gronau", "Germany", "48599", StateNRW},
+		{"Osnabrück", "Germany", "49074", StateNiedersachsen},
+		{"Ibbenbüren", "Germany", "49477", StateNRW},
+		{"Lingen", "DE", "49808", StateNiedersachsen},
+		{"Emden", "Deutschland", "26721", StateNiedersachsen},
+		{"Kleve", "", "47533", StateNRW}, // Leeg land = Duitsland
+		{"Aachen", "germany", "52062", StateNRW},
+		{"Bonn", "Germany", "53111", StateNRW},
+		{"Bielefeld", "Germany", "33602", StateNRW},
+
+		// Andere deelstaten en onbekende reeksen
+		{"Bremerhaven", "Germany", "27568", ""},
+		{"Salzwedel", "Germany", "29410", ""},
+		{"Remagen", "Germany", "53424", ""},
+		{"Koblenz", "Germany", "56068", ""},
+		{"München", "Germany", "80331", ""},
+
+		// Niet in Duitsland
+		{"Venlo", "Netherlands", "5911", ""},
+		{"Venlo met spatie", "Netherlands", "5911 AB", ""},
+		{"Enschede", "Nederland", "7511 JD", ""},
+		{"Heerlen", "NL", "6411", ""},
+		{"NL land, Duits ogende postcode", "Netherlands", "48143", ""},
+		{"Eupen", "Belgium", "4700", ""},
+		{"onbekend land", "Atlantis", "48143", ""},
+
+		// Geen vijfcijferige postcode
+		{"leeg", "Germany", "", ""},
+		{"vier cijfers", "Germany", "4814", ""},
+		{"Nederlandse vorm", "Germany", "5911AB", ""},
+		{"zes cijfers", "Germany", "481431", ""},
+		{"letters", "Germany", "48a43", ""},
+		{"teken", "Germany", "+4814", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.place, func(t *testing.T) {
+			if got := StateForPostalCode(tt.country, tt.postalCode); got != tt.want {
+				t.Fatalf("StateForPostalCode(%q, %q) = %q, want %q", tt.country, tt.postalCode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostalRangesSorted(t *testing.T) {
+	for i, r := range postalRanges {
+		if r.From > r.To {
+			t.Errorf("range %d-%d is reversed", r.From, r.To)
+		}
+		if i > 0 && postalRanges[i-1].To >= r.From {
+			t.Errorf("range %d-%d overlaps %d-%d", r.From, r.To, postalRanges[i-1].From, postalRanges[i-1].To)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package images verwerkt geüploade foto's: type controleren, EXIF oriëntatie
+// toepassen, opnieuw encoderen (daarmee verdwijnt alle metadata, ook GPS) en
+// verkleinde varianten maken.
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // GIF decoder registreren
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// MaxPixels - grotere afbeeldingen weigeren we vóór het decoderen (decompression bombs)
+const MaxPixels = 40_000_000
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type (use JPEG, PNG or GIF)")
+	ErrTooLarge        = errors.New("image dimensions too large")
+)
+
+// Variant - een gegenereerde maat; MaxSize is de langste zijde in pixels
+type Variant struct {
+	Name    string
+	MaxSize int
+}
+
+// Variants - maten die voor elke upload gemaakt worden
+var Variants = []Variant{
+	{Name: "large", MaxSize: 1600},
+	{Name: "medium", MaxSize: 800},
+	{Name: "thumb", MaxSize: 320},
+}
+
+// Output - één gecodeerde variant
+type Output struct {
+	Variant     string
+	ContentType string
+	Ext         string
+	Data        []byte
+	Width       int
+	Height      int
+}
+
+// Result - verwerkte upload
+type Result struct {
+	SourceType string // Gedetecteerd type van het origineel
+	Width      int    // Afmetingen na oriëntatie
+	Height     int
+	Outputs    []Output
+}
+
+// Sniff bepaalt het type aan de hand van de inhoud (niet de bestandsnaam of header)
+func Sniff(data []byte) (string, error) {
+	switch contentType := http.DetectContentType(data); contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return contentType, nil
+	default:
+		return contentType, ErrUnsupportedType
+	}
+}
+
+// Process controleert, draait en verkleint een afbeelding
+func Process(data []byte) (*Result, error) {
+	sourceType, err := Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	img := toRGBA(decoded)
+	if sourceType == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	// PNG en GIF kunnen transparant zijn (logo's): die blijven PNG
+	keepAlpha := sourceType != "image/jpeg"
+
+	result := &Result{
+		SourceType: sourceType,
+		Width:      img.Bounds().Dx(),
+		Height:     img.Bounds().Dy(),
+	}
+
+	for _, variant := range Variants {
+		width, height := fit(result.Width, result.Height, variant.MaxSize)
+		resized := img
+		if width != result.Width || height != result.Height {
+			resized = resize(img, width, height)
+		}
+
+		output := Output{Variant: variant.Name, Width: width, Height: height}
+		var buf bytes.Buffer
+		if keepAlpha {
+			output.ContentType, output.Ext = "image/png", "png"
+			err = png.Encode(&buf, resized)
+		} else {
+			output.ContentType, output.Ext = "image/jpeg", "jpg"
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85})
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		output.Data = buf.Bytes()
+		result.Outputs = append(result.Outputs, output)
+	}
+
+	return result, nil
+}
+
+// fit - afmetingen binnen maxSize met behoud van verhouding (nooit vergroten)
+func fit(width, height, maxSize int) (int, int) {
+	if width <= maxSize && height <= maxSize {
+		return width, height
+	}
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// resize verkleint met een box filter (gemiddelde van alle bronpixels per doelpixel)
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		sy0 := y * sh / height
+		sy1 := max(sy0+1, (y+1)*sh/height)
+
+		for x := 0; x < width; x++ {
+			sx0 := x * sw / width
+			sx1 := max(sx0+1, (x+1)*sw/width)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				offset := src.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
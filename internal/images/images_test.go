@@ -0,0 +1,201 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+const gpsMarker = "GPS 52.2215N 6.8937E" // Moet na het verwerken verdwenen zijn
+
+// exifSegment - APP1 segment met een TIFF blok (IFD0 met alleen de Orientation tag)
+// plus wat extra metadata die er na het verwerken niet meer in mag staan
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8) // IFD0 direct na de header
+	order.PutUint16(tiff[8:], 1) // Eén entry
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	tiff = append(tiff, gpsMarker...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// withExif zet een EXIF segment direct na de SOI marker van een JPEG
+func withExif(jpg []byte, segment []byte) []byte {
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, segment...)
+	return append(out, jpg[2:]...)
+}
+
+// testJPEG - 40x20: links rood, rechts blauw
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 20 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xC000 && g < 0x4000 && b < 0x4000
+}
+
+func isBlue(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return b > 0xC000 && r < 0x4000 && g < 0x4000
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	base := testJPEG(t)
+
+	tests := map[string]struct {
+		data []byte
+		want int
+	}{
+		"no exif":             {base, 1},
+		"little endian":       {withExif(base, exifSegment(binary.LittleEndian, 6)), 6},
+		"big endian":          {withExif(base, exifSegment(binary.BigEndian, 8)), 8},
+		"out of range value":  {withExif(base, exifSegment(binary.LittleEndian, 9)), 1},
+		"not a jpeg":          {[]byte("GIF89a........"), 1},
+		"empty":               {nil, 1},
+		"truncated segment":   {withExif(base, exifSegment(binary.LittleEndian, 6))[:20], 1},
+		"bad segment length":  {[]byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x01, 0x00}, 1},
+		"missing marker byte": {[]byte{0xFF, 0xD8, 0x00, 0xE1, 0x00, 0x10}, 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != tt.want {
+				t.Fatalf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExifOrientationMalformed(t *testing.T) {
+	tests := map[string][]byte{
+		"short":            []byte("II*\x00"),
+		"unknown order":    []byte("XX*\x00\x08\x00\x00\x00\x00\x00"),
+		"ifd past end":     []byte("II*\x00\xff\x00\x00\x00\x00\x00"),
+		"entries past end": []byte("II*\x00\x08\x00\x00\x00\x05\x00"),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := exifOrientation(data); got != 1 {
+				t.Fatalf("got %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestProcessAppliesOrientation(t *testing.T) {
+	// 6 = 90° met de klok mee: de linkerkant (rood) komt boven
+	result, err := Process(withExif(testJPEG(t), exifSegment(binary.LittleEndian, 6)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Width != 20 || result.Height != 40 {
+		t.Fatalf("expected 20x40 after rotation, got %dx%d", result.Width, result.Height)
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(result.Outputs[0].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isRed(decoded.At(10, 5)) || !isBlue(decoded.At(10, 35)) {
+		t.Fatalf("unexpected colours after rotation: top %v, bottom %v", decoded.At(10, 5), decoded.At(10, 35))
+	}
+}
+
+func TestProcessStripsMetadata(t *testing.T) {
+	input := withExif(testJPEG(t), exifSegment(binary.BigEndian, 1))
+	if !bytes.Contains(input, []byte(gpsMarker)) {
+		t.Fatal("test input should contain the metadata marker")
+	}
+
+	result, err := Process(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Outputs) != len(Variants) {
+		t.Fatalf("expected %d variants, got %d", len(Variants), len(result.Outputs))
+	}
+	for _, output := range result.Outputs {
+		if bytes.Contains(output.Data, []byte("Exif")) || bytes.Contains(output.Data, []byte(gpsMarker)) {
+			t.Errorf("variant %s still contains EXIF metadata", output.Variant)
+		}
+		if output.ContentType != "image/jpeg" {
+			t.Errorf("variant %s: content type %s", output.Variant, output.ContentType)
+		}
+	}
+}
+
+func TestProcessKeepsPNGTransparency(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2000, 1000))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, output := range result.Outputs {
+		if output.ContentType != "image/png" {
+			t.Errorf("variant %s: content type %s", output.Variant, output.ContentType)
+		}
+	}
+	if large := result.Outputs[0]; large.Width != 1600 || large.Height != 800 {
+		t.Fatalf("large variant should be scaled to 1600x800, got %dx%d", large.Width, large.Height)
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	if _, err := Process([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>")); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("svg: expected ErrUnsupportedType, got %v", err)
+	}
+
+	// PNG header van 10000x10000 pixels: geweigerd vóór het decoderen
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], 10000)
+	binary.BigEndian.PutUint32(ihdr[4:], 10000)
+	ihdr[8], ihdr[9] = 8, 6 // 8 bit RGBA
+	chunk := append([]byte("IHDR"), ihdr...)
+	bomb := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d")
+	bomb = append(bomb, chunk...)
+	bomb = binary.BigEndian.AppendUint32(bomb, crc32.ChecksumIEEE(chunk))
+
+	if _, err := Process(bomb); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("decompression bomb: expected ErrTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Fields - velden die uit een spreadsheet geïmporteerd kunnen worden
+var Fields = []string{
+	"external_id", "name", "category", "sub_category", "address", "city", "postal_code",
+	"country", "state", "latitude", "longitude", "phone", "website", "email", "description",
+}
+
+// headerAliases - kolomnamen zoals ze in IHK/KvK lijsten voorkomen (genormaliseerd)
+var headerAliases = map[string][]string{
+	"external_id":  {"externalid", "id", "kennung", "kennummer", "ihknummer", "kvknummer", "nummer", "nr"},
+	"name":         {"name", "naam", "firma", "firmenname", "bedrijfsnaam", "unternehmen", "company"},
+	"category":     {"category", "categorie", "kategorie", "branche", "sector"},
+	"sub_category": {"subcategory", "subcategorie", "unterkategorie", "subkategorie", "kueche", "keuken"},
+	"address":      {"address", "adres", "adresse", "strasse", "straat", "street", "strassehausnummer"},
+	"city":         {"city", "stad", "plaats", "ort", "stadt", "woonplaats"},
+	"postal_code":  {"postalcode", "postcode", "plz", "zip", "postleitzahl"},
+	"country":      {"country", "land"},
+	"state":        {"state", "bundesland", "deelstaat"},
+	"latitude":     {"latitude", "lat", "breitengrad", "breedtegraad"},
+	"longitude":    {"longitude", "lng", "lon", "laengengrad", "lengtegraad"},
+	"phone":        {"phone", "telefon", "telefoon", "tel", "telefonnummer", "telefoonnummer"},
+	"website":      {"website", "homepage", "internet", "url", "webseite"},
+	"email":        {"email", "mail", "emailadresse", "emailadres"},
+	"description":  {"description", "beschreibung", "omschrijving", "beschrijving"},
+}
+
+// IsField controleert een veldnaam uit een mapping
+func IsField(field string) bool {
+	_, ok := headerAliases[field]
+	return ok
+}
+
+// normalizeHeader - "E-Mail Adresse" -> "emailadresse", "Straße" -> "strasse"
+func normalizeHeader(header string) string {
+	replacer := strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+	header = replacer.Replace(strings.ToLower(header))
+
+	var b strings.Builder
+	for _, r := range header {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// BuildMapping bepaalt per veld de kolom. explicit (veld -> kolomnaam) gaat voor
+// de automatische herkenning op kolomnaam.
+func BuildMapping(header []string, explicit map[string]string) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		if _, exists := columns[normalizeHeader(name)]; !exists {
+			columns[normalizeHeader(name)] = i
+		}
+	}
+
+	mapping := map[string]int{}
+	for field, column := range explicit {
+		if !IsField(field) {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+		if column == "" {
+			continue
+		}
+		index, ok := columns[normalizeHeader(column)]
+		if !ok {
+			return nil, fmt.Errorf("column %q for field %q not found", column, field)
+		}
+		mapping[field] = index
+	}
+
+	for field, aliases := range headerAliases {
+		if _, ok := mapping[field]; ok {
+			continue
+		}
+		if _, skip := explicit[field]; skip { // "" in de mapping: veld bewust niet importeren
+			continue
+		}
+		for _, alias := range aliases {
+			if index, ok := columns[alias]; ok {
+				mapping[field] = index
+				break
+			}
+		}
+	}
+
+	if _, ok := mapping["name"]; !ok {
+		return nil, fmt.Errorf("no column found for required field name")
+	}
+
+	return mapping, nil
+}
+
+// Values - de gemapte waarden van een rij (alleen velden met een kolom)
+func Values(row Row, mapping map[string]int) map[string]string {
+	values := make(map[string]string, len(mapping))
+	for field, index := range mapping {
+		if index < len(row.Values) {
+			values[field] = strings.TrimSpace(row.Values[index])
+		} else {
+			values[field] = ""
+		}
+	}
+	return values
+}
+
+// ParseCoordinate accepteert "51.2277" en "51,2277"
+func ParseCoordinate(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(strings.TrimSpace(value), ",", ".", 1), 64)
+}
+
+// MappingResponse - veld -> kolomnaam, voor het rapport
+func MappingResponse(header []string, mapping map[string]int) map[string]string {
+	response := make(map[string]string, len(mapping))
+	for field, index := range mapping {
+		response[field] = header[index]
+	}
+	return response
+}
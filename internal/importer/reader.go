@@ -0,0 +1,127 @@
+// Package importer leest spreadsheets (CSV en XLSX) met bedrijfsgegevens,
+// bijvoorbeeld lijsten van de IHK/Kamer van Koophandel.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxRows - grotere bestanden in delen aanleveren
+const MaxRows = 5000
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format (use .csv or .xlsx)")
+	ErrEmptyFile         = errors.New("file contains no header row")
+	ErrTooManyRows       = errors.New("too many rows")
+)
+
+// Row - datarij met het regelnummer zoals in het bestand (voor het rapport)
+type Row struct {
+	Number int
+	Values []string
+}
+
+// Sheet - kopregel plus datarijen
+type Sheet struct {
+	Header []string
+	Rows   []Row
+}
+
+// ReadFile leest een CSV of XLSX bestand; het formaat volgt uit de inhoud of extensie
+func ReadFile(filename string, data []byte) (*Sheet, error) {
+	var records []Row
+	var err error
+
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")): // zip: xlsx
+		records, err = readXLSX(data)
+	case strings.EqualFold(filepath.Ext(filename), ".csv") || strings.EqualFold(filepath.Ext(filename), ".txt"):
+		records, err = readCSV(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Lege regels overslaan
+	var rows []Row
+	for _, record := range records {
+		if !emptyRecord(record.Values) {
+			rows = append(rows, record)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if len(rows)-1 > MaxRows {
+		return nil, ErrTooManyRows
+	}
+
+	header := make([]string, len(rows[0].Values))
+	for i, name := range rows[0].Values {
+		header[i] = strings.TrimSpace(name)
+	}
+
+	return &Sheet{Header: header, Rows: rows[1:]}, nil
+}
+
+// readCSV - komma of puntkomma (Duitse/Nederlandse Excel exports), met of zonder BOM
+func readCSV(data []byte) ([]Row, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		data = latin1ToUTF8(data)
+	}
+
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	delimiter := ','
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		delimiter = ';'
+	} else if bytes.Count(firstLine, []byte("\t")) > bytes.Count(firstLine, []byte(",")) {
+		delimiter = '\t'
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows []Row
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, Row{Number: line, Values: values})
+	}
+}
+
+// latin1ToUTF8 - oude Excel CSV exports zijn vaak Windows-1252/ISO-8859-1
+func latin1ToUTF8(data []byte) []byte {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
+
+func emptyRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
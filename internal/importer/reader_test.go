@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVDelimiters(t *testing.T) {
+	tests := map[string]string{
+		"comma":     "Name,City,PLZ\nCafé Müller,Aachen,52062\n",
+		"semicolon": "Name;City;PLZ\nCafé Müller;Aachen;52062\n",
+		"tab":       "Name\tCity\tPLZ\nCafé Müller\tAachen\t52062\n",
+		"bom":       "\xef\xbb\xbfName;City;PLZ\r\nCafé Müller;Aachen;52062\r\n",
+		// Komma in een kolomnaam: de puntkomma's winnen (3 tegen 1)
+		"semicolon with comma in header": "Name;\"Straße, Nr\";City;PLZ\nCafé Müller;Markt 1;Aachen;52062\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			sheet, err := ReadFile("export.csv", []byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sheet.Header[0] != "Name" || len(sheet.Rows) != 1 {
+				t.Fatalf("header %q, %d rows", sheet.Header, len(sheet.Rows))
+			}
+			row := sheet.Rows[0].Values
+			if row[0] != "Café Müller" || row[len(row)-1] != "52062" {
+				t.Fatalf("row %q", row)
+			}
+		})
+	}
+}
+
+func TestReadCSVCommaDecimals(t *testing.T) {
+	// Duitse export: puntkomma als scheiding, komma als decimaalteken
+	data := "Firma;Breitengrad;Längengrad\nCafé;50,7753;6,0839\n"
+	sheet, err := ReadFile("export.csv", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Café", "50,7753", "6,0839"}; !reflect.DeepEqual(sheet.Rows[0].Values, want) {
+		t.Fatalf("got %q, want %q", sheet.Rows[0].Values, want)
+	}
+	if lat, err := ParseCoordinate(sheet.Rows[0].Values[1]); err != nil || lat != 50.7753 {
+		t.Fatalf("coordinate %v, %v", lat, err)
+	}
+}
+
+func TestReadCSVLatin1Fallback(t *testing.T) {
+	// "Straße;Ort\nMarkt 1;Düsseldorf" als ISO-8859-1
+	data := []byte("Stra\xdfe;Ort\nMarkt 1;D\xfcsseldorf\n")
+
+	sheet, err := ReadFile("export.csv", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sheet.Header[0] != "Straße" || sheet.Rows[0].Values[1] != "Düsseldorf" {
+		t.Fatalf("latin-1 not converted: header %q, row %q", sheet.Header, sheet.Rows[0].Values)
+	}
+}
+
+func TestReadCSVKeepsValidUTF8(t *testing.T) {
+	// Geldige UTF-8 mag niet nog eens omgezet worden ("Ã¼")
+	sheet, err := ReadFile("export.csv", []byte("Ort\nDüsseldorf\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sheet.Rows[0].Values[0]; got != "Düsseldorf" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestReadCSVRowNumbers(t *testing.T) {
+	// Lege regels worden overgeslagen, maar de regelnummers blijven die uit het bestand
+	data := "Name,City\n\nA,Aachen\n,,\n , \nB,Bonn\n"
+	sheet, err := ReadFile("export.txt", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []int
+	for _, row := range sheet.Rows {
+		numbers = append(numbers, row.Number)
+	}
+	if !reflect.DeepEqual(numbers, []int{3, 6}) {
+		t.Fatalf("got row numbers %v", numbers)
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		filename string
+		data     string
+		want     error
+	}{
+		"unknown extension": {"export.ods", "Name\nA\n", ErrUnsupportedFormat},
+		"empty csv":         {"export.csv", "", ErrEmptyFile},
+		"only blank lines":  {"export.csv", "\n , \n\n", ErrEmptyFile},
+		"too many rows":     {"export.csv", "Name\n" + strings.Repeat("A\n", MaxRows+1), ErrTooManyRows},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadFile(tt.filename, []byte(tt.data)); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := ReadFile("export.csv", []byte("Name\n"+strings.Repeat("A\n", MaxRows))); err != nil {
+		t.Fatalf("exactly MaxRows rows should be accepted: %v", err)
+	}
+}
+
+func TestBuildMapping(t *testing.T) {
+	header := []string{"Kennung", "Firmenname", "Straße", "PLZ", "Ort", "E-Mail Adresse", "Breitengrad", "Längengrad"}
+
+	mapping, err := BuildMapping(header, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{
+		"external_id": 0, "name": 1, "address": 2, "postal_code": 3, "city": 4,
+		"email": 5, "latitude": 6, "longitude": 7,
+	}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Fatalf("got %v, want %v", mapping, want)
+	}
+
+	// Expliciete mapping gaat voor, "" sluit een veld uit
+	mapping, err = BuildMapping(header, map[string]string{"address": "Ort", "city": "", "email": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping["address"] != 4 {
+		t.Errorf("explicit mapping ignored: %v", mapping)
+	}
+	if _, ok := mapping["city"]; ok {
+		t.Errorf("city should be skipped: %v", mapping)
+	}
+
+	if _, err := BuildMapping(header, map[string]string{"colour": "Ort"}); err == nil {
+		t.Error("unknown field should be rejected")
+	}
+	if _, err := BuildMapping(header, map[string]string{"phone": "Telefon"}); err == nil {
+		t.Error("missing column should be rejected")
+	}
+	if _, err := BuildMapping([]string{"PLZ", "Ort"}, nil); err == nil {
+		t.Error("a sheet without a name column should be rejected")
+	}
+}
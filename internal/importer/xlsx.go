@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Maximale uitgepakte grootte per XML onderdeel (zip bombs)
+const maxXLSXPartSize = 50 << 20
+
+// readXLSX leest het eerste werkblad van een .xlsx bestand (Office Open XML).
+// Alleen celwaarden; opmaak en formules worden genegeerd (de berekende waarde telt).
+func readXLSX(data []byte) ([]Row, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(file); err != nil {
+			return nil, err
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("xlsx: worksheet %s not found", sheetPath)
+	}
+
+	return readWorksheet(file, shared)
+}
+
+func readXMLPart(file *zip.File, target interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return xml.NewDecoder(io.LimitReader(reader, maxXLSXPartSize)).Decode(target)
+}
+
+// firstSheetPath zoekt het eerste werkblad via workbook.xml en de relaties
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	file, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("xlsx: workbook.xml not found")
+	}
+	if err := readXMLPart(file, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx: workbook has no sheets")
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if file, ok := files["xl/_rels/workbook.xml.rels"]; ok {
+		if err := readXMLPart(file, &rels); err != nil {
+			return "", err
+		}
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].RID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+// xlsxText - tekst met optionele opmaak-runs (<r><t>..</t></r>)
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(file *zip.File) ([]string, error) {
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if err := readXMLPart(file, &sst); err != nil {
+		return nil, err
+	}
+
+	shared := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		shared[i] = item.String()
+	}
+	return shared, nil
+}
+
+func readWorksheet(file *zip.File, shared []string) ([]Row, error) {
+	var sheet struct {
+		Rows []struct {
+			Number int `xml:"r,attr"`
+			Cells  []struct {
+				Ref    string    `xml:"r,attr"`
+				Type   string    `xml:"t,attr"`
+				Value  string    `xml:"v"`
+				Inline *xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := readXMLPart(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	records := make([]Row, 0, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		var record []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			if column < 0 || column > 1000 {
+				continue
+			}
+			for len(record) <= column {
+				record = append(record, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err == nil && index >= 0 && index < len(shared) {
+					record[column] = shared[index]
+				}
+			case "inlineStr":
+				if cell.Inline != nil {
+					record[column] = cell.Inline.String()
+				}
+			default: // n, str, b
+				record[column] = cell.Value
+			}
+		}
+		number := row.Number
+		if number == 0 {
+			number = i + 1
+		}
+		records = append(records, Row{Number: number, Values: record})
+	}
+
+	return records, nil
+}
+
+// columnIndex - "C7" -> 2
+func columnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+	}
+	return index - 1
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+const (
+	testWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+ <sheets><sheet name="Betriebe" sheetId="1" r:id="rId3"/><sheet name="Notizen" sheetId="2" r:id="rId1"/></sheets>
+</workbook>`
+
+	testRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+ <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+ <Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>
+</Relationships>`
+
+	// Index 2 heeft opmaak-runs; die moeten als één string terugkomen
+	testSharedStrings = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="5" uniqueCount="5">
+ <si><t>Kennung</t></si>
+ <si><t>Firmenname</t></si>
+ <si><r><rPr><b/></rPr><t>Café </t></r><r><t>Müller</t></r></si>
+ <si><t xml:space="preserve"> Aachen </t></si>
+ <si><t>PLZ</t></si>
+</sst>`
+
+	// Rij 2 is leeg, rij 3 heeft een gat (B3 ontbreekt) en een inline string
+	testSheet = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+ <sheetData>
+  <row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>4</v></c><c r="D1" t="inlineStr"><is><t>Ort</t></is></c></row>
+  <row r="2"><c r="A2"/></row>
+  <row r="3"><c r="A3"><v>1001</v></c><c r="C3"><f>52000+62</f><v>52062</v></c><c r="D3" t="s"><v>3</v></c></row>
+  <row r="4"><c r="A4" t="str"><v>1002</v></c><c r="B4" t="s"><v>2</v></c><c r="D4" t="s"><v>99</v></c></row>
+ </sheetData>
+</worksheet>`
+)
+
+// buildXLSX - minimaal .xlsx bestand met de gegeven onderdelen
+func buildXLSX(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range parts {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSXSharedStrings(t *testing.T) {
+	data := buildXLSX(t, map[string]string{
+		"[Content_Types].xml":        `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`,
+		"xl/workbook.xml":            testWorkbook,
+		"xl/_rels/workbook.xml.rels": testRels,
+		"xl/sharedStrings.xml":       testSharedStrings,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>wrong sheet</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml":   testSheet,
+	})
+
+	// De extensie doet er niet toe: zip inhoud is xlsx
+	sheet, err := ReadFile("upload.bin", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"Kennung", "Firmenname", "PLZ", "Ort"}; !reflect.DeepEqual(sheet.Header, want) {
+		t.Fatalf("header %q, want %q (first sheet in workbook order)", sheet.Header, want)
+	}
+	want := []Row{
+		{Number: 3, Values: []string{"1001", "", "52062", " Aachen "}},
+		{Number: 4, Values: []string{"1002", "Café Müller", "", ""}}, // Index 99 bestaat niet
+	}
+	if !reflect.DeepEqual(sheet.Rows, want) {
+		t.Fatalf("rows\n got %q\nwant %q", sheet.Rows, want)
+	}
+
+	mapping, err := BuildMapping(sheet.Header, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := Values(sheet.Rows[0], mapping)
+	if values["city"] != "Aachen" || values["postal_code"] != "52062" || values["external_id"] != "1001" {
+		t.Fatalf("mapped values %v", values)
+	}
+}
+
+func TestReadXLSXWithoutRelationships(t *testing.T) {
+	// Zonder workbook.xml.rels en sharedStrings.xml: sheet1.xml met alleen inline strings en getallen
+	data := buildXLSX(t, map[string]string{
+		"xl/workbook.xml": testWorkbook,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row><c t="inlineStr"><is><t>Name</t></is></c><c t="inlineStr"><is><t>Lat</t></is></c></row>
+			<row><c t="inlineStr"><is><r><t>Zur </t></r><r><t>Post</t></r></is></c><c><v>51.5</v></c></row>
+		</sheetData></worksheet>`,
+	})
+
+	sheet, err := ReadFile("export.xlsx", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Row{{Number: 2, Values: []string{"Zur Post", "51.5"}}}
+	if !reflect.DeepEqual(sheet.Header, []string{"Name", "Lat"}) || !reflect.DeepEqual(sheet.Rows, want) {
+		t.Fatalf("header %q rows %q", sheet.Header, sheet.Rows)
+	}
+}
+
+func TestReadXLSXMalformed(t *testing.T) {
+	tests := map[string]map[string]string{
+		"no workbook": {"xl/worksheets/sheet1.xml": testSheet},
+		"no sheets":   {"xl/workbook.xml": `<workbook><sheets/></workbook>`},
+		"missing worksheet": {
+			"xl/workbook.xml":            testWorkbook,
+			"xl/_rels/workbook.xml.rels": testRels,
+		},
+		"broken shared strings": {
+			"xl/workbook.xml":          testWorkbook,
+			"xl/sharedStrings.xml":     `<sst><si><t>open`,
+			"xl/worksheets/sheet1.xml": testSheet,
+		},
+		"broken worksheet": {
+			"xl/workbook.xml":          testWorkbook,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row>`,
+		},
+	}
+	for name, parts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadFile("export.xlsx", buildXLSX(t, parts)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	// Zip handtekening maar kapot archief
+	if _, err := ReadFile("export.xlsx", []byte("PK\x03\x04 not really a zip")); err == nil {
+		t.Fatal("expected an error for a corrupt zip")
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	for ref, want := range map[string]int{"A1": 0, "C7": 2, "Z10": 25, "AA3": 26, "AB12": 27, "1": -1} {
+		if got := columnIndex(ref); got != want {
+			t.Errorf("columnIndex(%q) = %d, want %d", ref, got, want)
+		}
+	}
+}
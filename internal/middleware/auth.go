@@ -1,19 +1,47 @@
 package middleware
 
 import (
-	"fmt"
+	"log"
 	"net/http"
-	"os"
 	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-// AuthMiddleware controleert JWT token
+// authenticateAPIKey handelt requests van machine clients af
+func authenticateAPIKey(c *gin.Context, rawKey string) {
+	apiKey, err := services.AuthenticateAPIKey(rawKey, c.ClientIP())
+	if err != nil {
+		log.Printf("🔑 Rejected API key from %s: %s %s", c.ClientIP(), c.Request.Method, c.Request.URL.Path)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		c.Abort()
+		return
+	}
+
+	// Set in context (geen user_id/role, alleen scopes)
+	c.Set("api_key_id", apiKey.ID)
+	c.Set("api_key_name", apiKey.Name)
+	c.Set("scopes", apiKey.Scopes)
+
+	c.Next()
+
+	// Elk gebruik moet herleidbaar zijn
+	log.Printf("🔑 API key %d (%s, ppk_%s) %s %s -> %d from %s",
+		apiKey.ID, apiKey.Name, apiKey.Prefix,
+		c.Request.Method, c.Request.URL.Path, c.Writer.Status(), c.ClientIP())
+}
+
+// AuthMiddleware controleert JWT token of API key
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Machine clients sturen een X-API-Key header
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateAPIKey(c, apiKey)
+			return
+		}
+
 		// Try to get token from cookie first
 		tokenString, err := c.Cookie("token")
 
@@ -36,88 +64,64 @@ func AuthMiddleware() gin.HandlerFunc {
 			tokenString = parts[1]
 		}
 
-		// Get JWT secret
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			secret = "default-secret-key-change-in-production" // Fallback
-		}
-
-		// Validate JWT token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method")
-			}
-			return []byte(secret), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+		// API key als Bearer token
+		if services.IsAPIKey(tokenString) {
+			authenticateAPIKey(c, tokenString)
 			return
 		}
 
-		// Extract claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			userID, ok1 := claims["user_id"].(float64)
-			role, ok2 := claims["role"].(string)
-
-			if !ok1 || !ok2 {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-				c.Abort()
-				return
-			}
-
-			// Set in context (consistent keys!)
-			c.Set("user_id", uint(userID))
-			c.Set("role", models.Role(role)) // ✅ FIXED: Convert to models.Role type
-		} else {
+		// Validate JWT token (signature, kid and expiry)
+		claims, err := services.ValidateToken(tokenString)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		// Set in context (consistent keys!)
+		c.Set("user_id", claims.UserID)
+		c.Set("role", claims.Role)
+
 		c.Next()
 	}
 }
 
-// AdminOnly middleware - alleen voor admin users
-func AdminOnly() gin.HandlerFunc {
+// OptionalAuthMiddleware - voor publieke routes die ingelogde gebruikers herkennen.
+// Zonder token of API key gaat het request anoniem door; een ongeldig token wordt wel geweigerd.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	auth := AuthMiddleware()
 	return func(c *gin.Context) {
-		role, exists := c.Get("role") // ✅ FIXED: Use "role" key
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "User role not found",
-			})
-			c.Abort()
+		token, _ := c.Cookie("token")
+		if token == "" && c.GetHeader("Authorization") == "" && c.GetHeader("X-API-Key") == "" {
+			c.Next()
 			return
 		}
-
-		// Type assertion to models.Role
-		userRole, ok := role.(models.Role)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid role type",
-			})
-			c.Abort()
-			return
-		}
-
-		if userRole != models.RoleAdmin {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Admin access required",
-			})
-			c.Abort()
-			return
-		}
-
-		c.Next()
+		auth(c)
 	}
 }
 
-// StudentOrAdmin middleware - voor studenten en admin
-func StudentOrAdmin() gin.HandlerFunc {
+// RequirePermission middleware - controleert of de rol van de gebruiker (of de scopes
+// van de API key) alle opgegeven permissies bevat
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role") // ✅ FIXED: Use "role" key
+		// API key: scopes in plaats van rol
+		if value, isAPIKey := c.Get("scopes"); isAPIKey {
+			scopes, _ := value.(models.StringList)
+			for _, permission := range permissions {
+				if !scopes.Contains(string(permission)) {
+					c.JSON(http.StatusForbidden, gin.H{
+						"error": "API key lacks required scope",
+						"scope": permission,
+					})
+					c.Abort()
+					return
+				}
+			}
+			c.Next()
+			return
+		}
+
+		role, exists := c.Get("role")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "User role not found",
@@ -136,12 +140,15 @@ func StudentOrAdmin() gin.HandlerFunc {
 			return
 		}
 
-		if userRole != models.RoleAdmin && userRole != models.RoleStudent {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Access denied",
-			})
-			c.Abort()
-			return
+		for _, permission := range permissions {
+			if !services.HasPermission(userRole, permission) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error":      "Access denied",
+					"permission": permission,
+				})
+				c.Abort()
+				return
+			}
 		}
 
 		c.Next()
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// APIKey - sleutel voor machine clients (import scripts, Next.js SSR)
+type APIKey struct {
+	ID      uint       `json:"id" gorm:"primaryKey"`
+	Name    string     `json:"name" gorm:"not null"`
+	Prefix  string     `json:"prefix" gorm:"uniqueIndex;not null"` // Zichtbaar deel, voor herkenning in logs
+	KeyHash string     `json:"-" gorm:"not null"`                  // SHA-256 van de volledige sleutel
+	Scopes  StringList `json:"scopes" gorm:"type:jsonb;not null"`  // Zelfde namen als permissies, bv. businesses:write
+
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+
+	CreatedByUserID uint `json:"created_by_user_id"`
+	CreatedBy       User `json:"created_by,omitempty" gorm:"foreignKey:CreatedByUserID"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsUsable controleert of de sleutel niet ingetrokken of verlopen is
+func (k *APIKey) IsUsable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	if k.ExpiresAt != nil && now.After(*k.ExpiresAt) {
+		return false
+	}
+	return true
+}
+
+// HasScope controleert of de sleutel een permissie heeft
+func (k *APIKey) HasScope(p Permission) bool {
+	return k.Scopes.Contains(string(p))
+}
@@ -2,15 +2,24 @@ package models
 
 import "time"
 
+// GeocodeStatus waarden
+const (
+	GeocodeOK              = "ok"               // Coördinaten passen bij de postcode
+	GeocodeFilled          = "geocoded"         // Coördinaten ontbraken, ingevuld met het midden van de postcode
+	GeocodeMismatch        = "mismatch"         // Coördinaten liggen ver van de postcode
+	GeocodeUnknownPostcode = "unknown_postcode" // Postcode niet in de dataset
+)
+
 type Business struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
 	Name        string    `json:"name" gorm:"not null"`
-	Category    string    `json:"category" gorm:"not null"` // restaurant, tankstation, supermarkt
-	SubCategory string    `json:"sub_category"`             // grieks, italiaans, etc.
+	Category    string    `json:"category" gorm:"not null"` // Slug van een hoofdcategorie (zie Category)
+	SubCategory string    `json:"sub_category"`             // Slug van een subcategorie
 	Address     string    `json:"address"`
 	City        string    `json:"city"`
-	Country     string    `json:"country" gorm:"default:'Germany'"`
+	Country     string    `json:"country"` // Leeg: onbekend (OSM import zonder land of herkenbare postcode)
 	PostalCode  string    `json:"postal_code"`
+	State       string    `json:"state" gorm:"type:varchar(2);index"` // Bundesland (NW, NI), voor feestdagen
 	Latitude    float64   `json:"latitude"`
 	Longitude   float64   `json:"longitude"`
 	Phone       string    `json:"phone"`
@@ -22,4 +31,56 @@ type Business struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	CustomerID  *uint     `json:"customer_id"`
 	Customer    Customer  `json:"customer,omitempty" gorm:"foreignKey:CustomerID"`
+
+	// Controle van de coördinaten tegen de postcode (zie GeocodeStatus constanten)
+	GeocodeStatus     string   `json:"geocode_status" gorm:"type:varchar(20);index"`
+	GeocodeDistanceKm *float64 `json:"geocode_distance_km,omitempty"` // Afstand tot het midden van de postcode
+
+	// Tankerkönig station ID (UUID) voor tankstations; koppelt de prijsfeed aan het bedrijf
+	FuelStationID *string     `json:"fuel_station_id,omitempty" gorm:"uniqueIndex"`
+	FuelPrices    []FuelPrice `json:"fuel_prices,omitempty" gorm:"-"` // Actuele prijzen (detailpagina)
+
+	// Samengevoegd met een ander bedrijf (blijft als inactief record bestaan, zodat
+	// imports het niet opnieuw aanmaken en oude links doorverwijzen)
+	MergedIntoID *uint `json:"merged_into_id,omitempty" gorm:"index"`
+
+	// Herkomst bij bulk import; een nieuwe import van dezelfde bron werkt bij op ExternalID
+	ImportSource string  `json:"import_source,omitempty" gorm:"type:varchar(50);uniqueIndex:idx_business_external"`
+	ExternalID   *string `json:"external_id,omitempty" gorm:"uniqueIndex:idx_business_external"`
+	// Waarden zoals bij de laatste import; velden die sindsdien door een admin zijn
+	// gewijzigd worden bij een nieuwe import niet overschreven
+	ImportSnapshot StringMap `json:"-" gorm:"type:jsonb"`
+
+	// Vertalingen (nl, de, en); bij publieke endpoints al toegepast op Description
+	Translations    []BusinessTranslation `json:"translations,omitempty" gorm:"foreignKey:BusinessID"`
+	Language        string                `json:"language,omitempty" gorm:"-"`
+	CategoryName    string                `json:"category_name,omitempty" gorm:"-"`
+	SubCategoryName string                `json:"sub_category_name,omitempty" gorm:"-"`
+
+	// Foto's en logo; ThumbnailURL/LogoURL worden in lijsten gevuld
+	Photos       []BusinessPhoto `json:"photos,omitempty" gorm:"foreignKey:BusinessID"`
+	ThumbnailURL string          `json:"thumbnail_url,omitempty" gorm:"-"`
+	LogoURL      string          `json:"logo_url,omitempty" gorm:"-"`
+
+	// Gemiddelde en aantal goedgekeurde reviews (alleen bijgewerkt door RefreshBusinessRating)
+	RatingAverage *float64 `json:"rating_average" gorm:"<-:false"`
+	RatingCount   int      `json:"rating_count" gorm:"<-:false;not null;default:0"`
+
+	// Lopende aanbiedingen (detailpagina)
+	Deals []Deal `json:"deals,omitempty" gorm:"foreignKey:BusinessID"`
+
+	// Openingstijden
+	OpeningHours    []OpeningHours          `json:"opening_hours,omitempty" gorm:"foreignKey:BusinessID"`
+	HoursExceptions []OpeningHoursException `json:"hours_exceptions,omitempty" gorm:"foreignKey:BusinessID"`
+	OpenNow         *bool                   `json:"open_now,omitempty" gorm:"-"`
+
+	// Actueel niveau uit het abonnement van de klant (berekend in de query, zie services.ListingTierSQL)
+	ListingTier string `json:"listing_tier,omitempty" gorm:"column:listing_tier;->;-:migration"`
+
+	// Alleen gevuld bij geo zoeken / ?q= (berekend in de query, geen kolom)
+	DistanceKm *float64 `json:"distance_km,omitempty" gorm:"column:distance_km;->;-:migration"`
+	Relevance  *float64 `json:"relevance,omitempty" gorm:"column:relevance;->;-:migration"`
+
+	// Alleen gevuld met ?from= (Nederlandse postcode of lat,lng)
+	Travel *TravelInfo `json:"travel,omitempty" gorm:"-"`
 }
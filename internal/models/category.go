@@ -0,0 +1,90 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Category - beheerde categorieboom. Business.Category verwijst naar een
+// hoofdcategorie en Business.SubCategory naar een subcategorie, beide via Slug.
+// Namen per taal staan in CategoryTranslation (op slug).
+type Category struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	Slug      string     `json:"slug" gorm:"not null;uniqueIndex"`
+	ParentID  *uint      `json:"parent_id" gorm:"index"`
+	Icon      string     `json:"icon"` // Icoonnaam voor de frontend (bv. "utensils")
+	SortOrder int        `json:"sort_order" gorm:"default:0"`
+	IsActive  bool       `json:"is_active" gorm:"default:true"`
+	Children  []Category `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Vertaalde naam voor de gevraagde taal / alle talen (niet opgeslagen)
+	Name  string            `json:"name,omitempty" gorm:"-"`
+	Names map[string]string `json:"names,omitempty" gorm:"-"`
+}
+
+// DefaultCategory - startinhoud van de categorieboom
+type DefaultCategory struct {
+	Slug     string
+	Icon     string
+	Children []string
+}
+
+// DefaultCategories - de categorieën die tot nu toe als vrije tekst gebruikt werden
+func DefaultCategories() []DefaultCategory {
+	return []DefaultCategory{
+		{Slug: "restaurant", Icon: "utensils", Children: []string{"deutsch", "grieks", "italiaans"}},
+		{Slug: "tankstation", Icon: "fuel"},
+		{Slug: "supermarkt", Icon: "shopping-cart"},
+	}
+}
+
+// CategoryAliases - bekende schrijfwijzen van bestaande vrije tekst naar een slug
+func CategoryAliases() map[string]string {
+	return map[string]string{
+		"restaurants":    "restaurant",
+		"gaststaette":    "restaurant",
+		"tankstelle":     "tankstation",
+		"tankstations":   "tankstation",
+		"gas-station":    "tankstation",
+		"petrol-station": "tankstation",
+		"supermarket":    "supermarkt",
+		"supermarkten":   "supermarkt",
+		"duits":          "deutsch",
+		"german":         "deutsch",
+		"griechisch":     "grieks",
+		"greek":          "grieks",
+		"italienisch":    "italiaans",
+		"italian":        "italiaans",
+	}
+}
+
+// NormalizeCategory zet vrije tekst om naar een slug, met bekende schrijfwijzen
+func NormalizeCategory(value string) string {
+	slug := CategorySlug(value)
+	if alias, ok := CategoryAliases()[slug]; ok {
+		return alias
+	}
+	return slug
+}
+
+// CategorySlug maakt een slug van vrije tekst: "Griechisch Küche" -> "griechisch-kueche"
+func CategorySlug(value string) string {
+	replacer := strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss", "é", "e", "ë", "e", "ï", "i")
+	value = replacer.Replace(strings.ToLower(strings.TrimSpace(value)))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range value {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -11,6 +11,29 @@ const (
 	StatusCancelled CustomerStatus = "cancelled"
 )
 
+// Vermeldingsniveaus uit het abonnement van de klant
+const (
+	TierBasic    = "basic"
+	TierFeatured = "featured" // Badge en uitgelichte marker op de kaart
+	TierPremium  = "premium"  // Als featured, en bovenaan in de lijst
+)
+
+// ListingTiers - van laag naar hoog
+var ListingTiers = []string{TierBasic, TierFeatured, TierPremium}
+
+// ListingTierHighlighted - featured en premium krijgen een badge en uitgelichte marker
+func ListingTierHighlighted(tier string) bool {
+	return tier == TierFeatured || tier == TierPremium
+}
+
+// ListingTierBoost - plaats in de standaardsortering: alleen premium staat bovenaan
+func ListingTierBoost(tier string) int {
+	if tier == TierPremium {
+		return 1
+	}
+	return 0
+}
+
 type Customer struct {
 	ID uint `json:"id" gorm:"primaryKey"`
 
@@ -37,6 +60,7 @@ type Customer struct {
 	// Financial
 	MonthlyFee     float64 `json:"monthly_fee" gorm:"default:0"`
 	CommissionRate float64 `json:"commission_rate" gorm:"default:10"` // Percentage voor student
+	ListingTier    string  `json:"listing_tier" gorm:"type:varchar(10);not null;default:'basic'"`
 
 	// Notes
 	Notes string `json:"notes" gorm:"type:text"`
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Codes bij een deal
+const (
+	DealCodeNone   = "none"   // Alleen tonen, geen code
+	DealCodeShared = "shared" // Vaste code (bijv. "NL10"), zichtbaar na inwisselen
+	DealCodeUnique = "unique" // Elke inwisseling krijgt een eigen code
+)
+
+// Deal - aanbieding van een betalende klant op de pagina van het bedrijf
+type Deal struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	BusinessID  uint      `json:"business_id" gorm:"not null;index"`
+	Business    *Business `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+	Title       string    `json:"title" gorm:"not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	Terms       string    `json:"terms" gorm:"type:text"` // Voorwaarden
+	StartsAt    time.Time `json:"starts_at" gorm:"not null;index"`
+	EndsAt      time.Time `json:"ends_at" gorm:"not null;index"`
+	IsActive    bool      `json:"is_active" gorm:"not null"`
+
+	CodeMode string `json:"code_mode" gorm:"type:varchar(10);default:'none'"`
+	Code     string `json:"code,omitempty"` // Vaste code; niet in publieke responses
+
+	MaxPerVisitor int `json:"max_per_visitor" gorm:"not null"` // 0 = onbeperkt, anders alleen met login (geen gorm default: die zou 0 overschrijven)
+	MaxTotal      int `json:"max_total" gorm:"default:0"`      // 0 = onbeperkt
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Aantal keer ingewisseld (berekend in de query, geen kolom)
+	RedemptionCount *int64 `json:"redemption_count,omitempty" gorm:"column:redemption_count;->;-:migration"`
+}
+
+// DealRedemption - één keer inwisselen door een bezoeker
+type DealRedemption struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	DealID      uint      `json:"deal_id" gorm:"not null;index:idx_deal_redemption_visitor,priority:1"`
+	VisitorHash string    `json:"-" gorm:"type:varchar(64);not null;index:idx_deal_redemption_visitor,priority:2"` // SHA-256 van het bezoeker ID
+	ClientHash  string    `json:"-" gorm:"type:varchar(64);index"`                                                 // SHA-256 van de ingelogde gebruiker (leeg zonder login)
+	Code        string    `json:"code,omitempty" gorm:"type:varchar(20);index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
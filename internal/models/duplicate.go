@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// DuplicateDismissal - paar bedrijven dat een admin als "geen dubbele" heeft gemarkeerd.
+// BusinessAID is altijd het kleinste ID.
+type DuplicateDismissal struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	BusinessAID       uint      `json:"business_a_id" gorm:"not null;uniqueIndex:idx_duplicate_pair"`
+	BusinessBID       uint      `json:"business_b_id" gorm:"not null;uniqueIndex:idx_duplicate_pair"`
+	DismissedByUserID *uint     `json:"dismissed_by_user_id"`
+	CreatedAt         time.Time `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Brandstofsoorten (namen zoals in de Tankerkönig API, plus LPG)
+const (
+	FuelE5     = "e5"
+	FuelE10    = "e10"
+	FuelDiesel = "diesel"
+	FuelLPG    = "lpg"
+)
+
+var FuelTypes = []string{FuelE5, FuelE10, FuelDiesel, FuelLPG}
+
+// IsValidFuelType controleert of een brandstofsoort bekend is
+func IsValidFuelType(fuel string) bool {
+	for _, known := range FuelTypes {
+		if known == fuel {
+			return true
+		}
+	}
+	return false
+}
+
+// FuelPrice - prijs per liter op een moment. Er komt alleen een nieuwe rij bij als
+// de prijs verandert, zodat de tabel de prijshistorie per station is. Een aanlevering
+// met dezelfde prijs schuift alleen LastConfirmedAt van de laatste rij op.
+type FuelPrice struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	BusinessID      uint      `json:"business_id" gorm:"not null;index:idx_fuel_price_history,priority:1"`
+	FuelType        string    `json:"fuel_type" gorm:"type:varchar(10);not null;index:idx_fuel_price_history,priority:2"`
+	Price           float64   `json:"price" gorm:"type:numeric(5,3);not null"` // EUR per liter
+	ObservedAt      time.Time `json:"observed_at" gorm:"not null;index:idx_fuel_price_history,priority:3"`
+	LastConfirmedAt time.Time `json:"last_confirmed_at" gorm:"index"` // Laatste aanlevering met deze prijs
+	Source          string    `json:"source" gorm:"type:varchar(50)"` // API key naam of "admin"
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// FuelReferencePrice - Nederlandse adviesprijs om de besparing tegen af te zetten
+type FuelReferencePrice struct {
+	FuelType  string    `json:"fuel_type" gorm:"primaryKey;type:varchar(10)"`
+	Price     float64   `json:"price" gorm:"type:numeric(5,3);not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UserIdentity - koppeling tussen een gebruiker en een externe (OIDC) login
+type UserIdentity struct {
+	ID     uint `json:"id" gorm:"primaryKey"`
+	UserID uint `json:"user_id" gorm:"not null;index"`
+	User   User `json:"-" gorm:"foreignKey:UserID"`
+
+	Issuer  string `json:"issuer" gorm:"not null;uniqueIndex:idx_identity_issuer_subject"`
+	Subject string `json:"subject" gorm:"not null;uniqueIndex:idx_identity_issuer_subject"`
+	Email   string `json:"email"`
+
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
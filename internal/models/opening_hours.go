@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// HolidayWeekday - weekdag waarde voor openingstijden op feestdagen
+const HolidayWeekday = 7
+
+// OpeningHours - vaste openingstijden per weekdag (0 = zondag ... 6 = zaterdag, 7 = feestdag).
+// Tijden als "HH:MM"; sluittijd vóór openingstijd betekent tot na middernacht.
+type OpeningHours struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	BusinessID uint   `json:"business_id" gorm:"not null;index"`
+	Weekday    int    `json:"weekday" gorm:"not null"`
+	Opens      string `json:"opens" gorm:"type:varchar(5);not null"`
+	Closes     string `json:"closes" gorm:"type:varchar(5);not null"`
+}
+
+// OpeningHoursException - afwijkende openingstijden op een specifieke datum
+type OpeningHoursException struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index:idx_hours_exception_date"`
+	Date       time.Time `json:"date" gorm:"type:date;not null;index:idx_hours_exception_date"`
+	Closed     bool      `json:"closed"`
+	Opens      string    `json:"opens" gorm:"type:varchar(5)"`
+	Closes     string    `json:"closes" gorm:"type:varchar(5)"`
+	Note       string    `json:"note"` // bv. "Inventur" of "Heiligabend"
+}
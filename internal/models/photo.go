@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Soorten afbeeldingen per bedrijf
+const (
+	PhotoKindPhoto = "photo"
+	PhotoKindLogo  = "logo" // Maximaal één per bedrijf
+)
+
+// BusinessPhoto - geüploade foto of logo. De bestanden staan in de opslag onder
+// StoragePrefix/<variant>.<ext> (large, medium, thumb).
+type BusinessPhoto struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	BusinessID    uint      `json:"business_id" gorm:"not null;index"`
+	Kind          string    `json:"kind" gorm:"type:varchar(10);not null;default:'photo'"`
+	StoragePrefix string    `json:"-" gorm:"not null"`
+	Ext           string    `json:"-" gorm:"type:varchar(5);not null"`
+	Width         int       `json:"width"`
+	Height        int       `json:"height"`
+	SizeBytes     int64     `json:"size_bytes"`
+	SortOrder     int       `json:"sort_order" gorm:"default:0"`
+	IsPrimary     bool      `json:"is_primary" gorm:"default:false"`
+	Caption       string    `json:"caption"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Publieke URL per variant (niet opgeslagen)
+	URLs map[string]string `json:"urls,omitempty" gorm:"-"`
+}
+
+// Key - opslag key van een variant
+func (p *BusinessPhoto) Key(variant string) string {
+	return p.StoragePrefix + "/" + variant + "." + p.Ext
+}
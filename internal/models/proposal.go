@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Statussen van een wijzigingsvoorstel
+const (
+	ProposalPending   = "pending"
+	ProposalApproved  = "approved"
+	ProposalRejected  = "rejected"
+	ProposalWithdrawn = "withdrawn" // Door de eigenaar ingetrokken
+)
+
+// BusinessEditProposal - wijziging die een eigenaar voorstelt; een admin keurt hem goed
+// of af. Changes en Original bevatten per veld de nieuwe en de oude waarde (openingstijden
+// als tekst, zie services.FormatOpeningHours).
+type BusinessEditProposal struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	BusinessID       uint      `json:"business_id" gorm:"not null;index"`
+	Business         *Business `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+	CustomerID       uint      `json:"customer_id" gorm:"not null;index"`
+	ProposedByUserID uint      `json:"proposed_by_user_id" gorm:"not null"`
+	ProposedBy       *User     `json:"proposed_by,omitempty" gorm:"foreignKey:ProposedByUserID"`
+
+	Changes  StringMap `json:"changes" gorm:"type:jsonb;not null"`
+	Original StringMap `json:"original" gorm:"type:jsonb;not null"` // Waarden op het moment van voorstellen
+	Message  string    `json:"message" gorm:"type:text"`            // Toelichting van de eigenaar
+
+	Status           string     `json:"status" gorm:"type:varchar(10);not null;default:'pending';index"`
+	ReviewedByUserID *uint      `json:"reviewed_by_user_id,omitempty"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote       string     `json:"review_note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
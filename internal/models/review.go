@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Review statussen; alleen approved is publiek zichtbaar
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+	ReviewSpam     = "spam" // Door de spamfilter tegengehouden, wel in de moderatie wachtrij
+)
+
+// Review - beoordeling van een bezoeker (anoniem of ingelogd)
+type Review struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	BusinessID uint      `json:"business_id" gorm:"not null;index:idx_review_business_status,priority:1"`
+	Business   *Business `json:"business,omitempty" gorm:"foreignKey:BusinessID"`
+	UserID     *uint     `json:"user_id,omitempty" gorm:"index"` // Leeg bij anonieme bezoekers
+	AuthorName string    `json:"author_name" gorm:"not null"`
+
+	Rating    int        `json:"rating" gorm:"not null;check:rating BETWEEN 1 AND 5"`
+	Text      string     `json:"text" gorm:"type:text"`
+	Language  string     `json:"language" gorm:"type:varchar(2)"`
+	VisitDate *time.Time `json:"visit_date,omitempty" gorm:"type:date"`
+
+	Status      string     `json:"status" gorm:"type:varchar(10);not null;default:'pending';index:idx_review_business_status,priority:2"`
+	SpamScore   float64    `json:"spam_score,omitempty"`
+	SpamReasons StringList `json:"spam_reasons,omitempty" gorm:"type:jsonb"`
+	IPHash      string     `json:"-" gorm:"type:varchar(64);index"`
+	TextHash    string     `json:"-" gorm:"type:varchar(64);index"` // Voor het herkennen van gekopieerde teksten
+
+	ModeratedByUserID *uint      `json:"moderated_by_user_id,omitempty"`
+	ModeratedAt       *time.Time `json:"moderated_at,omitempty"`
+	ModerationNote    string     `json:"moderation_note,omitempty"`
+
+	// Publieke reactie van het bedrijf
+	Reply         string     `json:"reply,omitempty" gorm:"type:text"`
+	ReplyAt       *time.Time `json:"reply_at,omitempty"`
+	ReplyByUserID *uint      `json:"reply_by_user_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
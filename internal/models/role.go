@@ -0,0 +1,138 @@
+package models
+
+import "time"
+
+type Permission string
+
+const (
+	PermAll Permission = "*" // Alle rechten (admin)
+
+	PermUsersManage         Permission = "users:manage"
+	PermRolesManage         Permission = "roles:manage"
+	PermAPIKeysManage       Permission = "apikeys:manage"
+	PermBusinessesWrite     Permission = "businesses:write"
+	PermBusinessesDelete    Permission = "businesses:delete" // Definitief verwijderen
+	PermCustomersRead       Permission = "customers:read"
+	PermCustomersWrite      Permission = "customers:write"
+	PermCommunicationsRead  Permission = "communications:read"
+	PermCommunicationsWrite Permission = "communications:write"
+	PermInvoicesRead        Permission = "invoices:read"
+	PermInvoicesWrite       Permission = "invoices:write"
+	PermTeamRead            Permission = "team:read"        // Data van teamleden inzien
+	PermFuelPricesWrite     Permission = "fuelprices:write" // Brandstofprijzen aanleveren (Tankerkönig feed)
+	PermReviewsModerate     Permission = "reviews:moderate" // Reviews goedkeuren, afwijzen en beantwoorden
+	PermOwnerPortal         Permission = "portal:owner"     // Eigen bedrijven, voorstellen en facturen (eigenaren)
+)
+
+// AllPermissions bevat alle bekende permissies (voor validatie en de admin UI)
+var AllPermissions = []Permission{
+	PermUsersManage,
+	PermRolesManage,
+	PermAPIKeysManage,
+	PermBusinessesWrite,
+	PermBusinessesDelete,
+	PermCustomersRead,
+	PermCustomersWrite,
+	PermCommunicationsRead,
+	PermCommunicationsWrite,
+	PermInvoicesRead,
+	PermInvoicesWrite,
+	PermTeamRead,
+	PermFuelPricesWrite,
+	PermReviewsModerate,
+	PermOwnerPortal,
+}
+
+// IsValidPermission controleert of een permissie bestaat
+func IsValidPermission(p Permission) bool {
+	if p == PermAll {
+		return true
+	}
+	for _, known := range AllPermissions {
+		if known == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleDefinition - rol met bijbehorende permissies, opgeslagen in de database
+type RoleDefinition struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Name        Role       `json:"name" gorm:"unique;not null"`
+	Description string     `json:"description"`
+	Permissions StringList `json:"permissions" gorm:"type:jsonb;not null"`
+	BuiltIn     bool       `json:"built_in" gorm:"default:false"` // Ingebouwde rollen kunnen niet verwijderd worden
+
+	// Ingebouwde permissies die al eens toegevoegd zijn; wat een admin daarna weghaalt, blijft weg
+	SeededPermissions StringList `json:"-" gorm:"type:jsonb"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HasPermission controleert of de rol een permissie heeft
+func (r *RoleDefinition) HasPermission(p Permission) bool {
+	return r.Permissions.Contains(string(PermAll)) || r.Permissions.Contains(string(p))
+}
+
+// BuiltInRoles - standaard rollen die bij het seeden aangemaakt worden
+func BuiltInRoles() []RoleDefinition {
+	return []RoleDefinition{
+		{
+			Name:        RoleAdmin,
+			Description: "Volledige toegang",
+			Permissions: StringList{string(PermAll)},
+			BuiltIn:     true,
+		},
+		{
+			Name:        RoleStudent,
+			Description: "Eigen klanten en communicatie beheren",
+			Permissions: StringList{
+				string(PermCustomersRead),
+				string(PermCustomersWrite),
+				string(PermCommunicationsRead),
+				string(PermCommunicationsWrite),
+			},
+			BuiltIn: true,
+		},
+		{
+			Name:        RoleTeamLead,
+			Description: "Student met inzage in klanten van het eigen team",
+			Permissions: StringList{
+				string(PermCustomersRead),
+				string(PermCustomersWrite),
+				string(PermCommunicationsRead),
+				string(PermCommunicationsWrite),
+				string(PermTeamRead),
+			},
+			BuiltIn: true,
+		},
+		{
+			Name:        RoleFinance,
+			Description: "Alleen facturen",
+			Permissions: StringList{
+				string(PermInvoicesRead),
+				string(PermInvoicesWrite),
+			},
+			BuiltIn: true,
+		},
+		{
+			Name:        RoleEditor,
+			Description: "Alleen bedrijfsvermeldingen en reviews",
+			Permissions: StringList{
+				string(PermBusinessesWrite),
+				string(PermReviewsModerate),
+			},
+			BuiltIn: true,
+		},
+		{
+			Name:        RoleOwner,
+			Description: "Eigenaar: eigen bedrijven inzien, wijzigingen voorstellen en facturen bekijken",
+			Permissions: StringList{
+				string(PermOwnerPortal),
+			},
+			BuiltIn: true,
+		},
+	}
+}
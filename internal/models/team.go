@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Team - groep studenten per universiteit/cohort met een begeleidende coördinator
+type Team struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	Name       string `json:"name" gorm:"not null"`
+	University string `json:"university"`
+	Cohort     string `json:"cohort"` // bv. "2025-2026"
+
+	SupervisorID *uint `json:"supervisor_id"` // Coördinator die het team begeleidt
+	Supervisor   *User `json:"supervisor,omitempty" gorm:"foreignKey:SupervisorID"`
+
+	Members []User `json:"members,omitempty" gorm:"many2many:team_members"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
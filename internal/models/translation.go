@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// BusinessTranslation - vertaalde omschrijving van een bedrijf (nl, de, en)
+type BusinessTranslation struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	BusinessID  uint      `json:"business_id" gorm:"not null;uniqueIndex:idx_business_translation"`
+	Language    string    `json:"language" gorm:"type:varchar(2);not null;uniqueIndex:idx_business_translation"`
+	Description string    `json:"description" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryTranslation - weergavenaam van een categorie of subcategorie.
+// Slug is de waarde zoals die in Business.Category / SubCategory staat.
+type CategoryTranslation struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Slug      string    `json:"slug" gorm:"not null;uniqueIndex:idx_category_translation"`
+	Language  string    `json:"language" gorm:"type:varchar(2);not null;uniqueIndex:idx_category_translation"`
+	Name      string    `json:"name" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultCategoryTranslations - namen voor de bestaande categorieën en subcategorieën
+func DefaultCategoryTranslations() []CategoryTranslation {
+	names := map[string][3]string{ // slug: nl, de, en
+		"restaurant":  {"Restaurant", "Restaurant", "Restaurant"},
+		"tankstation": {"Tankstation", "Tankstelle", "Petrol station"},
+		"supermarkt":  {"Supermarkt", "Supermarkt", "Supermarket"},
+		"deutsch":     {"Duits", "Deutsch", "German"},
+		"grieks":      {"Grieks", "Griechisch", "Greek"},
+		"italiaans":   {"Italiaans", "Italienisch", "Italian"},
+	}
+
+	var translations []CategoryTranslation
+	for slug, name := range names {
+		for i, lang := range []string{"nl", "de", "en"} {
+			translations = append(translations, CategoryTranslation{Slug: slug, Language: lang, Name: name[i]})
+		}
+	}
+	return translations
+}
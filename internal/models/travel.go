@@ -0,0 +1,16 @@
+package models
+
+// TravelInfo - afstand en reistijd vanaf een vertrekpunt (?from=), wordt niet opgeslagen
+type TravelInfo struct {
+	StraightKm      float64        `json:"straight_km"`
+	RoadKm          float64        `json:"road_km"`          // Schatting: rechte lijn x omrijfactor
+	DurationMinutes int            `json:"duration_minutes"` // Schatting bij een gemiddelde snelheid
+	NearestBorder   BorderDistance `json:"nearest_border"`
+}
+
+// BorderDistance - dichtstbijzijnde grensovergang vanaf het bedrijf
+type BorderDistance struct {
+	Name       string  `json:"name"`
+	Road       string  `json:"road,omitempty"`
+	DistanceKm float64 `json:"distance_km"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringList slaat een lijst strings op als JSON kolom
+type StringList []string
+
+// Value implementeert driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implementeert sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = StringList{}
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// Contains controleert of de lijst een waarde bevat
+func (l StringList) Contains(value string) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// StringMap slaat sleutel/waarde paren op als JSON kolom
+type StringMap map[string]string
+
+// Value implementeert driver.Valuer
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(map[string]string(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implementeert sql.Scanner
+func (m *StringMap) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into StringMap", value)
+	}
+
+	return json.Unmarshal(data, (*map[string]string)(m))
+}
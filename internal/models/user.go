@@ -8,8 +8,12 @@ import (
 type Role string
 
 const (
-	RoleAdmin   Role = "admin"
-	RoleStudent Role = "student"
+	RoleAdmin    Role = "admin"
+	RoleStudent  Role = "student"
+	RoleTeamLead Role = "teamlead"
+	RoleFinance  Role = "finance"
+	RoleEditor   Role = "editor"
+	RoleOwner    Role = "owner" // Eigenaar van een bedrijf (klant), alleen het portaal
 )
 
 type User struct {
@@ -25,6 +29,9 @@ type User struct {
 	StudentID  string `json:"student_id"` // Studenten nummer
 	University string `json:"university"`
 
+	// Owner specific: de klant waarvan deze gebruiker de bedrijven beheert
+	CustomerID *uint `json:"customer_id,omitempty" gorm:"index"`
+
 	// Timestamps
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -0,0 +1,138 @@
+package osm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// POI - een OSM element omgezet naar bedrijfsvelden
+type POI struct {
+	Key          string // "node/123"
+	Name         string
+	Category     string // slug in de categorieboom
+	SubCategory  string
+	Address      string
+	City         string
+	PostalCode   string
+	Country      string
+	Latitude     float64
+	Longitude    float64
+	Phone        string
+	Website      string
+	Email        string
+	OpeningHours string // ruwe OSM opening_hours waarde
+}
+
+// categoryTags - OSM tag -> categorie slug
+var categoryTags = []struct {
+	Key, Value, Category string
+}{
+	{"amenity", "restaurant", "restaurant"},
+	{"amenity", "fuel", "tankstation"},
+	{"shop", "supermarket", "supermarkt"},
+}
+
+// cuisines - OSM cuisine -> subcategorie slug (alleen voor restaurants)
+var cuisines = map[string]string{
+	"german":   "deutsch",
+	"regional": "deutsch",
+	"greek":    "grieks",
+	"italian":  "italiaans",
+	"pizza":    "italiaans",
+}
+
+var countries = map[string]string{
+	"DE": "Germany",
+	"NL": "Netherlands",
+	"BE": "Belgium",
+}
+
+var (
+	dutchPostalCode  = regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`) // 7511 JD
+	germanPostalCode = regexp.MustCompile(`^\d{5}$`)           // 48143
+)
+
+// countryFromPostalCode - land op basis van de vorm van de postcode. In de grensregio
+// hebben de meeste nodes geen addr:country; vier cijfers (België, of NL zonder letters)
+// en ontbrekende postcodes blijven onbekend ("").
+func countryFromPostalCode(postalCode string) string {
+	postalCode = strings.ToUpper(strings.TrimSpace(postalCode))
+	switch {
+	case dutchPostalCode.MatchString(postalCode):
+		return countries["NL"]
+	case germanPostalCode.MatchString(postalCode):
+		return countries["DE"]
+	}
+	return ""
+}
+
+// IsCandidate - element met een tag die we importeren (los van naam en coördinaten)
+func IsCandidate(e *Element) bool {
+	return category(e) != ""
+}
+
+func category(e *Element) string {
+	for _, tag := range categoryTags {
+		if e.Tags[tag.Key] == tag.Value {
+			return tag.Category
+		}
+	}
+	return ""
+}
+
+// firstTag - eerste gevulde tag (bv. phone, dan contact:phone)
+func firstTag(e *Element, keys ...string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(e.Tags[key]); value != "" {
+			// Meerdere waarden zijn in OSM gescheiden door ";"; de eerste volstaat
+			first, _, _ := strings.Cut(value, ";")
+			return strings.TrimSpace(first)
+		}
+	}
+	return ""
+}
+
+// ToPOI zet een element om; false als het geen bruikbaar bedrijf is (geen naam of categorie).
+// Voor ways moeten Lat/Lon al op het middelpunt gezet zijn.
+func ToPOI(e *Element) (*POI, bool) {
+	cat := category(e)
+	name := strings.TrimSpace(e.Tags["name"])
+	if cat == "" || name == "" || (e.Lat == 0 && e.Lon == 0) {
+		return nil, false
+	}
+
+	poi := &POI{
+		Key:          e.Key(),
+		Name:         name,
+		Category:     cat,
+		City:         firstTag(e, "addr:city"),
+		PostalCode:   firstTag(e, "addr:postcode"),
+		Latitude:     e.Lat,
+		Longitude:    e.Lon,
+		Phone:        firstTag(e, "phone", "contact:phone"),
+		Website:      firstTag(e, "website", "contact:website", "url"),
+		Email:        firstTag(e, "email", "contact:email"),
+		OpeningHours: strings.TrimSpace(e.Tags["opening_hours"]),
+	}
+
+	if street := firstTag(e, "addr:street"); street != "" {
+		poi.Address = strings.TrimSpace(street + " " + firstTag(e, "addr:housenumber"))
+	}
+
+	if code := firstTag(e, "addr:country"); code != "" {
+		poi.Country = countries[strings.ToUpper(code)] // Ander land: onbekend
+	} else {
+		poi.Country = countryFromPostalCode(poi.PostalCode)
+	}
+
+	if cat == "restaurant" {
+		for _, cuisine := range strings.Split(e.Tags["cuisine"], ";") {
+			if sub, ok := cuisines[strings.TrimSpace(cuisine)]; ok {
+				poi.SubCategory = sub
+				break
+			}
+		}
+	}
+
+	return poi, true
+}
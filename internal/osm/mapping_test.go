@@ -0,0 +1,34 @@
+package osm
+
+import "testing"
+
+func TestToPOICountry(t *testing.T) {
+	tests := []struct {
+		name string
+		tags map[string]string
+		want string
+	}{
+		{"addr:country wins", map[string]string{"addr:country": "NL", "addr:postcode": "48143"}, "Netherlands"},
+		{"german postcode", map[string]string{"addr:postcode": "48143"}, "Germany"},
+		{"dutch postcode", map[string]string{"addr:postcode": "7511 JD"}, "Netherlands"},
+		{"dutch postcode without space", map[string]string{"addr:postcode": "5911ab"}, "Netherlands"},
+		{"four digits (Belgium or NL)", map[string]string{"addr:postcode": "5911"}, ""},
+		{"no postcode", map[string]string{}, ""},
+		{"other addr:country", map[string]string{"addr:country": "FR", "addr:postcode": "75001"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := map[string]string{"amenity": "restaurant", "name": "Test"}
+			for k, v := range tt.tags {
+				tags[k] = v
+			}
+			poi, ok := ToPOI(&Element{Type: "node", ID: 1, Lat: 51.37, Lon: 6.17, Tags: tags})
+			if !ok {
+				t.Fatal("expected a POI")
+			}
+			if poi.Country != tt.want {
+				t.Fatalf("country %q, want %q", poi.Country, tt.want)
+			}
+		})
+	}
+}
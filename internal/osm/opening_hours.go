@@ -0,0 +1,154 @@
+package osm
+
+import (
+	"errors"
+	"strings"
+)
+
+// Hours - één tijdvak; Weekday 0 = zondag ... 6 = zaterdag, 7 = feestdag
+// (zelfde indeling als models.OpeningHours)
+type Hours struct {
+	Weekday int
+	Opens   string
+	Closes  string
+}
+
+var ErrUnsupportedHours = errors.New("unsupported opening_hours syntax")
+
+// OSM dagvolgorde Mo..Su naar onze weekdagnummers
+var osmDays = map[string]int{"Mo": 1, "Tu": 2, "We": 3, "Th": 4, "Fr": 5, "Sa": 6, "Su": 0, "PH": 7}
+var osmDayOrder = []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}
+
+// ParseOpeningHours ondersteunt de gangbare vorm van de opening_hours tag:
+//
+//	"Mo-Fr 08:00-18:00; Sa 09:00-13:00,14:00-16:00; Su,PH off" en "24/7"
+//
+// Maanden, weken, zonsopgang, opmerkingen en dergelijke geven ErrUnsupportedHours;
+// dan importeren we geen openingstijden. Zonder PH regel is het bedrijf op
+// feestdagen dicht (zoals de meeste winkels in Duitsland); 24/7 geldt ook op feestdagen.
+func ParseOpeningHours(value string) ([]Hours, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if value == "24/7" {
+		var hours []Hours
+		for weekday := 0; weekday <= 7; weekday++ {
+			hours = append(hours, Hours{Weekday: weekday, Opens: "00:00", Closes: "24:00"})
+		}
+		return hours, nil
+	}
+
+	// Latere regels overschrijven eerdere voor dezelfde dagen
+	byDay := map[int][]Hours{}
+	for _, rule := range strings.Split(value, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		var days []int
+		selector, times, found := strings.Cut(rule, " ")
+		switch {
+		case !found && strings.Contains(rule, ":"): // "08:00-18:00": elke dag
+			days, times = []int{0, 1, 2, 3, 4, 5, 6}, rule
+		case !found:
+			return nil, ErrUnsupportedHours
+		default:
+			var err error
+			if days, err = parseDays(selector); err != nil {
+				return nil, err
+			}
+		}
+
+		times = strings.TrimSpace(times)
+		var spans []Hours
+		if times != "off" && times != "closed" {
+			for _, span := range strings.Split(times, ",") {
+				opens, closes, ok := strings.Cut(strings.TrimSpace(span), "-")
+				if !ok || !validOSMTime(opens) || !validOSMTime(closes) {
+					return nil, ErrUnsupportedHours
+				}
+				spans = append(spans, Hours{Opens: normalizeTime(opens), Closes: normalizeTime(closes)})
+			}
+		}
+
+		for _, day := range days {
+			byDay[day] = nil
+			for _, span := range spans {
+				span.Weekday = day
+				byDay[day] = append(byDay[day], span)
+			}
+		}
+	}
+
+	var hours []Hours
+	for day := 0; day <= 7; day++ {
+		hours = append(hours, byDay[day]...)
+	}
+	return hours, nil
+}
+
+// parseDays - "Mo-Fr", "Sa,Su", "Mo-We,Fr,PH"
+func parseDays(selector string) ([]int, error) {
+	var days []int
+	for _, part := range strings.Split(selector, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		if _, ok := osmDays[from]; !ok {
+			return nil, ErrUnsupportedHours
+		}
+		if !isRange {
+			days = append(days, osmDays[from])
+			continue
+		}
+
+		start, end := dayIndex(from), dayIndex(to)
+		if start < 0 || end < 0 {
+			return nil, ErrUnsupportedHours
+		}
+		// Ook over het weekend heen: "Fr-Mo"
+		for i := start; ; i = (i + 1) % 7 {
+			days = append(days, osmDays[osmDayOrder[i]])
+			if i == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func dayIndex(day string) int {
+	for i, d := range osmDayOrder {
+		if d == day {
+			return i
+		}
+	}
+	return -1
+}
+
+// validOSMTime - "8:00", "08:00", "24:00" en "26:00" (na middernacht) komen voor
+func validOSMTime(value string) bool {
+	hour, minute, ok := strings.Cut(value, ":")
+	if !ok || len(hour) < 1 || len(hour) > 2 || len(minute) != 2 {
+		return false
+	}
+	for _, r := range hour + minute {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return minute < "60" && (len(hour) == 1 || hour <= "48")
+}
+
+// normalizeTime - "8:00" -> "08:00", "26:00" -> "02:00" (loopt door na middernacht)
+func normalizeTime(value string) string {
+	hour, minute, _ := strings.Cut(value, ":")
+	if len(hour) == 1 {
+		hour = "0" + hour
+	}
+	if hour > "24" || (hour == "24" && minute != "00") {
+		h := (int(hour[0]-'0')*10 + int(hour[1]-'0')) - 24
+		hour = string([]byte{byte('0' + h/10), byte('0' + h%10)})
+	}
+	return hour + ":" + minute
+}
@@ -0,0 +1,96 @@
+package osm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formatHours - compacte weergave: "1 08:00-18:00, 2 08:00-18:00"
+func formatHours(hours []Hours) string {
+	parts := make([]string, len(hours))
+	for i, h := range hours {
+		parts[i] = fmt.Sprintf("%d %s-%s", h.Weekday, h.Opens, h.Closes)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestParseOpeningHours(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"Mo-Fr 08:00-18:00",
+			"1 08:00-18:00, 2 08:00-18:00, 3 08:00-18:00, 4 08:00-18:00, 5 08:00-18:00"},
+		{"Mo-Fr 08:00-18:00; Sa 09:00-13:00,14:00-16:00; Su,PH off",
+			"1 08:00-18:00, 2 08:00-18:00, 3 08:00-18:00, 4 08:00-18:00, 5 08:00-18:00, 6 09:00-13:00, 6 14:00-16:00"},
+		{"Mo-Sa 8:00-20:00",
+			"1 08:00-20:00, 2 08:00-20:00, 3 08:00-20:00, 4 08:00-20:00, 5 08:00-20:00, 6 08:00-20:00"},
+		{"08:00-18:00",
+			"0 08:00-18:00, 1 08:00-18:00, 2 08:00-18:00, 3 08:00-18:00, 4 08:00-18:00, 5 08:00-18:00, 6 08:00-18:00"},
+		{"24/7",
+			"0 00:00-24:00, 1 00:00-24:00, 2 00:00-24:00, 3 00:00-24:00, 4 00:00-24:00, 5 00:00-24:00, 6 00:00-24:00, 7 00:00-24:00"},
+		// Over het weekend heen en na middernacht
+		{"Fr-Mo 10:00-12:00,14:00-26:00",
+			"0 10:00-12:00, 0 14:00-02:00, 1 10:00-12:00, 1 14:00-02:00, 5 10:00-12:00, 5 14:00-02:00, 6 10:00-12:00, 6 14:00-02:00"},
+		// Latere regel overschrijft eerdere, ook met "closed"
+		{"Mo-Fr 09:00-17:00; We closed",
+			"1 09:00-17:00, 2 09:00-17:00, 4 09:00-17:00, 5 09:00-17:00"},
+		{"Sa 10:00-14:00; PH 11:00-13:00;", "6 10:00-14:00, 7 11:00-13:00"},
+		{"  Su 12:00-01:00  ", "0 12:00-01:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			hours, err := ParseOpeningHours(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := formatHours(hours); got != tt.want {
+				t.Fatalf("\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOpeningHoursUnsupported(t *testing.T) {
+	for _, value := range []string{
+		"sunrise-sunset",
+		"Mo-Fr 08:00-18:00; Dec 24 off",
+		"Mo-Fr 08:00-18:00 \"by appointment\"",
+		"week 1-26 Mo 10:00-12:00",
+		"Mo-Xx 08:00-18:00",
+		"Mon 08:00-18:00",
+		"Mo-Fr",
+		"Mo-Fr 08:00",
+		"Mo-Fr 08:00-",
+		"Mo-Fr 8-18",
+		"Mo-Fr 08:60-18:00",
+		"Mo-Fr 49:00-50:00",
+		"Mo-Fr 08:00-18:0a",
+		"Mo-Fr 08:00-18:00,",
+		"open",
+	} {
+		t.Run(value, func(t *testing.T) {
+			if _, err := ParseOpeningHours(value); !errors.Is(err, ErrUnsupportedHours) {
+				t.Fatalf("expected ErrUnsupportedHours, got %v", err)
+			}
+		})
+	}
+}
+
+// TestFixtureOpeningHours - de opening_hours waarden uit de fixtures zijn allemaal bruikbaar
+func TestFixtureOpeningHours(t *testing.T) {
+	for _, path := range []string{"testdata/sample.osm.pbf", "testdata/sample.osm"} {
+		for _, e := range readAll(t, path) {
+			value, ok := e.Tags["opening_hours"]
+			if !ok {
+				continue
+			}
+			if hours, err := ParseOpeningHours(value); err != nil || len(hours) == 0 {
+				t.Errorf("%s %s: %q gave %v, %v", path, e.Key(), value, hours, err)
+			}
+		}
+	}
+}
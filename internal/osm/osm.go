@@ -0,0 +1,42 @@
+// Package osm leest OpenStreetMap extracts (.osm XML en .osm.pbf) en zet
+// relevante POI's (restaurants, tankstations, supermarkten) om naar bedrijven.
+package osm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Element - node of way met tags. Ways hebben Refs; de coördinaten van een way
+// worden later als middelpunt van de nodes berekend.
+type Element struct {
+	Type string // "node" of "way"
+	ID   int64
+	Lat  float64
+	Lon  float64
+	Tags map[string]string
+	Refs []int64
+}
+
+// Key - "node/123", uniek binnen OSM
+func (e *Element) Key() string {
+	return fmt.Sprintf("%s/%d", e.Type, e.ID)
+}
+
+// Handler wordt per element aangeroepen
+type Handler func(e *Element) error
+
+// ReadFile leest een .osm.pbf of .osm (XML) bestand
+func ReadFile(path string, handle Handler) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(strings.ToLower(path), ".pbf") {
+		return ReadPBF(file, handle)
+	}
+	return ReadXML(file, handle)
+}
@@ -0,0 +1,422 @@
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Limieten uit de PBF specificatie
+const (
+	maxBlobHeaderSize = 64 << 10
+	maxBlobSize       = 32 << 20
+)
+
+var errMalformed = errors.New("pbf: malformed protobuf data")
+
+// ReadPBF leest een .osm.pbf bestand (zlib of ongecomprimeerde blobs).
+// Alleen nodes, dense nodes en ways; relaties worden overgeslagen.
+func ReadPBF(r io.Reader, handle Handler) error {
+	var sizeBuf [4]byte
+	for {
+		if _, err := io.ReadFull(r, sizeBuf[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		headerSize := binary.BigEndian.Uint32(sizeBuf[:])
+		if headerSize > maxBlobHeaderSize {
+			return fmt.Errorf("pbf: blob header too large (%d bytes)", headerSize)
+		}
+		header := make([]byte, headerSize)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return err
+		}
+
+		blobType, dataSize, err := parseBlobHeader(header)
+		if err != nil {
+			return err
+		}
+		if dataSize > maxBlobSize {
+			return fmt.Errorf("pbf: blob too large (%d bytes)", dataSize)
+		}
+
+		blob := make([]byte, dataSize)
+		if _, err := io.ReadFull(r, blob); err != nil {
+			return err
+		}
+
+		if blobType != "OSMData" {
+			continue // OSMHeader: geen elementen
+		}
+
+		data, err := decodeBlob(blob)
+		if err != nil {
+			return err
+		}
+		if err := readPrimitiveBlock(data, handle); err != nil {
+			return err
+		}
+	}
+}
+
+// --- protobuf wire format ---
+
+type protoField struct {
+	Number int
+	Wire   int
+	Varint uint64
+	Bytes  []byte
+}
+
+// protoFields loopt over de velden van een bericht
+func protoFields(data []byte, fn func(f protoField) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errMalformed
+		}
+		data = data[n:]
+
+		field := protoField{Number: int(key >> 3), Wire: int(key & 7)}
+		switch field.Wire {
+		case 0: // varint
+			value, n := binary.Uvarint(data)
+			if n <= 0 {
+				return errMalformed
+			}
+			field.Varint = value
+			data = data[n:]
+		case 1: // 64-bit
+			if len(data) < 8 {
+				return errMalformed
+			}
+			field.Varint = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case 2: // length-delimited
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return errMalformed
+			}
+			field.Bytes = data[n : n+int(length)]
+			data = data[n+int(length):]
+		case 5: // 32-bit
+			if len(data) < 4 {
+				return errMalformed
+			}
+			field.Varint = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return errMalformed
+		}
+
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packedVarints - packed repeated (u)int32/int64
+func packedVarints(data []byte) ([]uint64, error) {
+	var values []uint64
+	for len(data) > 0 {
+		value, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errMalformed
+		}
+		values = append(values, value)
+		data = data[n:]
+	}
+	return values, nil
+}
+
+// packedSint64 - packed sint64 (zigzag)
+func packedSint64(data []byte) ([]int64, error) {
+	raw, err := packedVarints(data)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]int64, len(raw))
+	for i, value := range raw {
+		values[i] = zigzag(value)
+	}
+	return values, nil
+}
+
+func zigzag(value uint64) int64 {
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+// --- OSM PBF berichten ---
+
+func parseBlobHeader(data []byte) (string, int, error) {
+	var blobType string
+	var dataSize int
+	err := protoFields(data, func(f protoField) error {
+		switch f.Number {
+		case 1:
+			blobType = string(f.Bytes)
+		case 3:
+			dataSize = int(int32(f.Varint))
+		}
+		return nil
+	})
+	if dataSize < 0 {
+		return "", 0, errMalformed
+	}
+	return blobType, dataSize, err
+}
+
+func decodeBlob(data []byte) ([]byte, error) {
+	var raw, compressed []byte
+	var rawSize int
+	unsupported := false
+
+	err := protoFields(data, func(f protoField) error {
+		switch f.Number {
+		case 1:
+			raw = f.Bytes
+		case 2:
+			rawSize = int(int32(f.Varint))
+		case 3:
+			compressed = f.Bytes
+		case 4, 5, 6, 7: // lzma, bzip2, lz4, zstd
+			unsupported = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case raw != nil:
+		return raw, nil
+	case compressed != nil:
+		if rawSize <= 0 || rawSize > maxBlobSize {
+			return nil, errMalformed
+		}
+		reader, err := zlib.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		out := make([]byte, rawSize)
+		if _, err := io.ReadFull(reader, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	case unsupported:
+		return nil, errors.New("pbf: unsupported blob compression (only zlib)")
+	default:
+		return nil, errMalformed
+	}
+}
+
+// primitiveBlock - gedeelde context voor de groepen in een blok
+type primitiveBlock struct {
+	strings     []string
+	granularity int64
+	latOffset   int64
+	lonOffset   int64
+}
+
+func (b *primitiveBlock) coordinate(offset, value int64) float64 {
+	return 1e-9 * float64(offset+b.granularity*value)
+}
+
+func (b *primitiveBlock) str(index uint64) string {
+	if index < uint64(len(b.strings)) {
+		return b.strings[index]
+	}
+	return ""
+}
+
+func readPrimitiveBlock(data []byte, handle Handler) error {
+	block := &primitiveBlock{granularity: 100}
+	var groups [][]byte
+
+	err := protoFields(data, func(f protoField) error {
+		switch f.Number {
+		case 1:
+			return protoFields(f.Bytes, func(s protoField) error {
+				if s.Number == 1 {
+					block.strings = append(block.strings, string(s.Bytes))
+				}
+				return nil
+			})
+		case 2:
+			groups = append(groups, f.Bytes)
+		case 17:
+			block.granularity = int64(int32(f.Varint))
+		case 19:
+			block.latOffset = int64(f.Varint)
+		case 20:
+			block.lonOffset = int64(f.Varint)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Groepen pas na het hele blok: granularity en offsets kunnen erna staan
+	for _, group := range groups {
+		err := protoFields(group, func(f protoField) error {
+			switch f.Number {
+			case 1:
+				return readNode(block, f.Bytes, handle)
+			case 2:
+				return readDenseNodes(block, f.Bytes, handle)
+			case 3:
+				return readWay(block, f.Bytes, handle)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tagsFromKeysVals - parallelle keys/vals indexen in de string table
+func tagsFromKeysVals(block *primitiveBlock, keys, vals []uint64) map[string]string {
+	tags := make(map[string]string, len(keys))
+	for i := 0; i < len(keys) && i < len(vals); i++ {
+		tags[block.str(keys[i])] = block.str(vals[i])
+	}
+	return tags
+}
+
+func readNode(block *primitiveBlock, data []byte, handle Handler) error {
+	element := &Element{Type: "node"}
+	var keys, vals []uint64
+	var lat, lon int64
+
+	err := protoFields(data, func(f protoField) (err error) {
+		switch f.Number {
+		case 1:
+			element.ID = zigzag(f.Varint)
+		case 2:
+			keys, err = packedVarints(f.Bytes)
+		case 3:
+			vals, err = packedVarints(f.Bytes)
+		case 8:
+			lat = zigzag(f.Varint)
+		case 9:
+			lon = zigzag(f.Varint)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	element.Tags = tagsFromKeysVals(block, keys, vals)
+	element.Lat = block.coordinate(block.latOffset, lat)
+	element.Lon = block.coordinate(block.lonOffset, lon)
+	return handle(element)
+}
+
+func readDenseNodes(block *primitiveBlock, data []byte, handle Handler) error {
+	var ids, lats, lons []int64
+	var keysVals []uint64
+
+	err := protoFields(data, func(f protoField) (err error) {
+		switch f.Number {
+		case 1:
+			ids, err = packedSint64(f.Bytes)
+		case 8:
+			lats, err = packedSint64(f.Bytes)
+		case 9:
+			lons, err = packedSint64(f.Bytes)
+		case 10:
+			keysVals, err = packedVarints(f.Bytes)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(lats) != len(ids) || len(lons) != len(ids) {
+		return errMalformed
+	}
+
+	// Alles is delta-gecodeerd; keys_vals is per node afgesloten met 0
+	var id, lat, lon int64
+	kv := 0
+	for i := range ids {
+		id += ids[i]
+		lat += lats[i]
+		lon += lons[i]
+
+		tags := map[string]string{}
+		for kv < len(keysVals) {
+			key := keysVals[kv]
+			kv++
+			if key == 0 {
+				break
+			}
+			if kv < len(keysVals) {
+				tags[block.str(key)] = block.str(keysVals[kv])
+				kv++
+			}
+		}
+
+		element := &Element{
+			Type: "node",
+			ID:   id,
+			Lat:  block.coordinate(block.latOffset, lat),
+			Lon:  block.coordinate(block.lonOffset, lon),
+			Tags: tags,
+		}
+		if err := handle(element); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readWay(block *primitiveBlock, data []byte, handle Handler) error {
+	element := &Element{Type: "way"}
+	var keys, vals []uint64
+	var refs []int64
+
+	err := protoFields(data, func(f protoField) (err error) {
+		switch f.Number {
+		case 1:
+			element.ID = int64(f.Varint)
+		case 2:
+			keys, err = packedVarints(f.Bytes)
+		case 3:
+			vals, err = packedVarints(f.Bytes)
+		case 8:
+			refs, err = packedSint64(f.Bytes)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	element.Tags = tagsFromKeysVals(block, keys, vals)
+
+	var ref int64
+	element.Refs = make([]int64, len(refs))
+	for i, delta := range refs {
+		ref += delta
+		element.Refs[i] = ref
+	}
+
+	return handle(element)
+}
@@ -0,0 +1,264 @@
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata/sample.osm.pbf en testdata/sample.osm bevatten dezelfde elementen:
+// een restaurant en vier losse punten als dense nodes, een tankstation als
+// gewone node en een supermarkt als way. Het XML bestand heeft daarnaast een
+// relatie die overgeslagen moet worden.
+
+func readAll(t *testing.T, path string) []*Element {
+	t.Helper()
+	var elements []*Element
+	err := ReadFile(path, func(e *Element) error {
+		elements = append(elements, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return elements
+}
+
+func TestReadPBFFixture(t *testing.T) {
+	elements := readAll(t, "testdata/sample.osm.pbf")
+
+	var keys []string
+	for _, e := range elements {
+		keys = append(keys, e.Key())
+	}
+	want := []string{"node/10", "node/11", "node/12", "node/13", "node/14", "node/20", "way/30"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("got %v, want %v", keys, want)
+	}
+
+	restaurant := elements[0]
+	if restaurant.Tags["name"] != "Taverna Kreta" || restaurant.Tags["cuisine"] != "greek" {
+		t.Errorf("dense node tags: %v", restaurant.Tags)
+	}
+	if math.Abs(restaurant.Lat-50.7753) > 1e-7 || math.Abs(restaurant.Lon-6.0839) > 1e-7 {
+		t.Errorf("dense node coordinates: %f,%f", restaurant.Lat, restaurant.Lon)
+	}
+	if len(elements[1].Tags) != 0 {
+		t.Errorf("node without tags got %v", elements[1].Tags)
+	}
+
+	fuel := elements[5]
+	if fuel.Tags["amenity"] != "fuel" || math.Abs(fuel.Lat-51.4) > 1e-7 || math.Abs(fuel.Lon-6.1) > 1e-7 {
+		t.Errorf("plain node: %+v", fuel)
+	}
+
+	way := elements[6]
+	if !reflect.DeepEqual(way.Refs, []int64{12, 13, 14, 12}) {
+		t.Errorf("way refs (delta decoded): %v", way.Refs)
+	}
+	if way.Tags["opening_hours"] != "24/7" {
+		t.Errorf("way tags: %v", way.Tags)
+	}
+}
+
+func TestReadXMLMatchesPBF(t *testing.T) {
+	fromPBF := readAll(t, "testdata/sample.osm.pbf")
+	fromXML := readAll(t, "testdata/sample.osm")
+
+	if len(fromXML) != len(fromPBF) {
+		t.Fatalf("xml has %d elements, pbf %d (relations must be skipped)", len(fromXML), len(fromPBF))
+	}
+	for i := range fromPBF {
+		p, x := fromPBF[i], fromXML[i]
+		if p.Key() != x.Key() || !reflect.DeepEqual(p.Tags, x.Tags) || len(p.Refs) != len(x.Refs) {
+			t.Errorf("element %d differs:\n pbf %+v\n xml %+v", i, p, x)
+		}
+		if math.Abs(p.Lat-x.Lat) > 1e-7 || math.Abs(p.Lon-x.Lon) > 1e-7 {
+			t.Errorf("%s: pbf %f,%f xml %f,%f", p.Key(), p.Lat, p.Lon, x.Lat, x.Lon)
+		}
+	}
+}
+
+func TestReadXMLMalformed(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.osm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := data[:bytes.Index(data, []byte("<way"))+20]
+	if err := ReadXML(bytes.NewReader(truncated), func(*Element) error { return nil }); err == nil {
+		t.Fatal("expected an error for truncated XML")
+	}
+}
+
+func TestReadPBFHandlerError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	err := ReadFile("testdata/sample.osm.pbf", func(*Element) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("handler error should stop reading: err %v after %d calls", err, calls)
+	}
+}
+
+// TestReadPBFTruncated - elk afgekapt bestand geeft een fout (en geen panic),
+// behalve precies op een blob grens
+func TestReadPBFTruncated(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.osm.pbf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	boundaries := map[int]bool{0: true}
+	for offset := 0; offset < len(data); {
+		headerSize := int(binary.BigEndian.Uint32(data[offset:]))
+		_, dataSize, err := parseBlobHeader(data[offset+4 : offset+4+headerSize])
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset += 4 + headerSize + dataSize
+		boundaries[offset] = true
+	}
+
+	for n := 0; n < len(data); n++ {
+		err := ReadPBF(bytes.NewReader(data[:n]), func(*Element) error { return nil })
+		if boundaries[n] && err != nil {
+			t.Errorf("cut at blob boundary %d: %v", n, err)
+		}
+		if !boundaries[n] && err == nil {
+			t.Errorf("cut at %d: expected an error", n)
+		}
+	}
+}
+
+// --- handgemaakte blobs voor kapotte invoer ---
+
+func protoKey(number, wire int) []byte {
+	return binary.AppendUvarint(nil, uint64(number<<3|wire))
+}
+
+func protoVarint(number int, value uint64) []byte {
+	return binary.AppendUvarint(protoKey(number, 0), value)
+}
+
+func protoBytes(number int, value []byte) []byte {
+	out := binary.AppendUvarint(protoKey(number, 2), uint64(len(value)))
+	return append(out, value...)
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// pbfFile - één OSMData blob met de gegeven blob inhoud
+func pbfFile(blob []byte) []byte {
+	header := concat(protoBytes(1, []byte("OSMData")), protoVarint(3, uint64(len(blob))))
+	out := binary.BigEndian.AppendUint32(nil, uint32(len(header)))
+	return concat(out, header, blob)
+}
+
+// rawBlock - ongecomprimeerde blob met een string table en één primitive group
+func rawBlock(group []byte) []byte {
+	stringTable := concat(protoBytes(1, nil), protoBytes(1, []byte("name")), protoBytes(1, []byte("Test")))
+	block := concat(protoBytes(1, stringTable), protoBytes(2, group))
+	return protoBytes(1, block)
+}
+
+func zlibBytes(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestReadPBFRawBlob(t *testing.T) {
+	node := concat(protoVarint(1, 14), protoBytes(2, []byte{1}), protoBytes(3, []byte{2}), protoVarint(8, 2*515000000), protoVarint(9, 2*62000000))
+	var got []*Element
+	err := ReadPBF(bytes.NewReader(pbfFile(rawBlock(protoBytes(1, node)))), func(e *Element) error {
+		got = append(got, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != 7 || got[0].Tags["name"] != "Test" || math.Abs(got[0].Lat-51.5) > 1e-7 {
+		t.Fatalf("unexpected elements %+v", got)
+	}
+}
+
+func TestReadPBFMalformed(t *testing.T) {
+	block := concat(protoBytes(1, concat(protoBytes(1, nil))), protoBytes(2, nil))
+
+	tests := map[string][]byte{
+		"header size too large": binary.BigEndian.AppendUint32(nil, maxBlobHeaderSize+1),
+		"blob size too large": func() []byte {
+			header := concat(protoBytes(1, []byte("OSMData")), protoVarint(3, maxBlobSize+1))
+			return concat(binary.BigEndian.AppendUint32(nil, uint32(len(header))), header)
+		}(),
+		"negative blob size": func() []byte {
+			header := concat(protoBytes(1, []byte("OSMData")), protoVarint(3, 0xFFFFFFFF))
+			return concat(binary.BigEndian.AppendUint32(nil, uint32(len(header))), header)
+		}(),
+		"invalid wire type":       pbfFile([]byte{0x0F}),
+		"length past end":         pbfFile(concat(protoKey(1, 2), []byte{0x7F, 0x01})),
+		"truncated varint":        pbfFile([]byte{0x10, 0x80}),
+		"empty blob":              pbfFile(nil),
+		"lzma compression":        pbfFile(protoBytes(4, []byte{1, 2, 3})),
+		"zlib without raw size":   pbfFile(protoBytes(3, zlibBytes(block))),
+		"zlib raw size too large": pbfFile(concat(protoVarint(2, maxBlobSize+1), protoBytes(3, zlibBytes(block)))),
+		"zlib raw size too small": pbfFile(concat(protoVarint(2, uint64(len(block)+10)), protoBytes(3, zlibBytes(block)))),
+		"corrupt zlib":            pbfFile(concat(protoVarint(2, 10), protoBytes(3, []byte("not zlib")))),
+		"dense nodes without coordinates": pbfFile(rawBlock(protoBytes(2,
+			protoBytes(1, []byte{2, 2})))),
+		"dense lats and lons differ": pbfFile(rawBlock(protoBytes(2, concat(
+			protoBytes(1, []byte{2}), protoBytes(8, []byte{2}), protoBytes(9, []byte{2, 4}))))),
+		"truncated packed varint": pbfFile(rawBlock(protoBytes(3,
+			concat(protoVarint(1, 1), protoBytes(8, []byte{0x80}))))),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ReadPBF(bytes.NewReader(data), func(*Element) error { return nil })
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestReadPBFOutOfRangeStrings(t *testing.T) {
+	// Key en value wijzen buiten de string table: lege strings, geen panic
+	node := concat(protoVarint(1, 2), protoBytes(2, []byte{99}), protoBytes(3, []byte{100}))
+	var tags map[string]string
+	err := ReadPBF(bytes.NewReader(pbfFile(rawBlock(protoBytes(1, node)))), func(e *Element) error {
+		tags = e.Tags
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tags, map[string]string{"": ""}) {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+}
+
+func FuzzReadPBF(f *testing.F) {
+	data, err := os.ReadFile("testdata/sample.osm.pbf")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+	f.Add(pbfFile(rawBlock(protoBytes(2, concat(protoBytes(1, []byte{2}), protoBytes(8, []byte{2}), protoBytes(9, []byte{2}))))))
+	f.Add([]byte(strings.Repeat("\x00", 8)))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ReadPBF(bytes.NewReader(data), func(*Element) error { return nil })
+	})
+}
@@ -0,0 +1,72 @@
+package osm
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// ReadXML leest een OSM XML bestand als stream (ook grote extracts)
+func ReadXML(r io.Reader, handle Handler) error {
+	decoder := xml.NewDecoder(r)
+
+	var current *Element
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "node", "way":
+				current = &Element{Type: t.Name.Local, Tags: map[string]string{}}
+				for _, attr := range t.Attr {
+					switch attr.Name.Local {
+					case "id":
+						current.ID, _ = strconv.ParseInt(attr.Value, 10, 64)
+					case "lat":
+						current.Lat, _ = strconv.ParseFloat(attr.Value, 64)
+					case "lon":
+						current.Lon, _ = strconv.ParseFloat(attr.Value, 64)
+					}
+				}
+			case "tag":
+				if current != nil {
+					var key, value string
+					for _, attr := range t.Attr {
+						switch attr.Name.Local {
+						case "k":
+							key = attr.Value
+						case "v":
+							value = attr.Value
+						}
+					}
+					current.Tags[key] = value
+				}
+			case "nd":
+				if current != nil {
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "ref" {
+							ref, _ := strconv.ParseInt(attr.Value, 10, 64)
+							current.Refs = append(current.Refs, ref)
+						}
+					}
+				}
+			case "relation":
+				current = nil
+			}
+		case xml.EndElement:
+			if (t.Name.Local == "node" || t.Name.Local == "way") && current != nil {
+				if err := handle(current); err != nil {
+					return err
+				}
+				current = nil
+			}
+		}
+	}
+}
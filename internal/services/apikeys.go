@@ -0,0 +1,78 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"strings"
+	"time"
+)
+
+// Sleutels zien eruit als ppk_<prefix>_<secret>
+const apiKeyPrefix = "ppk_"
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// IsAPIKey controleert of een token een API key is (en geen JWT)
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+// hashAPIKey - SHA-256 is genoeg, de sleutels zelf zijn lang en willekeurig
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAPIKey maakt een nieuwe sleutel; de volledige sleutel wordt alleen nu teruggegeven
+func GenerateAPIKey() (key string, prefix string, hash string, err error) {
+	prefixBytes := make([]byte, 4)
+	secretBytes := make([]byte, 32)
+
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	key = apiKeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	return key, prefix, hashAPIKey(key), nil
+}
+
+// AuthenticateAPIKey zoekt de sleutel op en controleert hash, intrekking en vervaldatum
+func AuthenticateAPIKey(key string, clientIP string) (*models.APIKey, error) {
+	parts := strings.SplitN(strings.TrimPrefix(key, apiKeyPrefix), "_", 2)
+	if !IsAPIKey(key) || len(parts) != 2 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	var apiKey models.APIKey
+	if err := config.DB.Where("prefix = ?", parts[0]).First(&apiKey).Error; err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hashAPIKey(key))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !apiKey.IsUsable(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Laatste gebruik bijhouden
+	config.DB.Model(&apiKey).UpdateColumns(map[string]interface{}{
+		"last_used_at": now,
+		"last_used_ip": clientIP,
+	})
+
+	return &apiKey, nil
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"errors"
-	"os"
 	"projectpeterperplexity/internal/models"
 	"time"
 
@@ -11,51 +10,41 @@ import (
 
 type Claims struct {
 	UserID uint        `json:"user_id"`
-	Email  string      `json:"email"`
+	Email  string      `json:"email,omitempty"`
 	Role   models.Role `json:"role"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken genereert JWT token voor gebruiker
+// GenerateToken genereert JWT token voor gebruiker (getekend met de actieve sleutel)
 func GenerateToken(userID uint, role models.Role) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
-
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"role":    role,
-		"exp":     expirationTime.Unix(),
-		"iat":     time.Now().Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Get JWT secret from environment
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "default-secret-key-change-in-production"
+	now := time.Now()
+
+	claims := Claims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
 	}
 
-	return token.SignedString([]byte(secret))
+	return signToken(claims)
 }
 
 // ValidateToken valideert JWT token
 func ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "default-secret-key-change-in-production"
-	}
-
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, verificationKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if !token.Valid {
+	if !token.Valid || claims.UserID == 0 || claims.Role == "" {
 		return nil, errors.New("invalid token")
 	}
 
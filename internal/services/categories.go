@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+)
+
+var (
+	ErrUnknownCategory    = errors.New("unknown or inactive category")
+	ErrUnknownSubCategory = errors.New("sub_category does not belong to category")
+)
+
+// ValidateBusinessCategory controleert dat category een actieve hoofdcategorie is
+// en sub_category (optioneel) een actieve subcategorie daarvan
+func ValidateBusinessCategory(category, subCategory string) error {
+	var parent models.Category
+	err := config.DB.Where("slug = ? AND parent_id IS NULL AND is_active = ?", category, true).First(&parent).Error
+	if err != nil {
+		return ErrUnknownCategory
+	}
+
+	if subCategory == "" {
+		return nil
+	}
+
+	var child models.Category
+	err = config.DB.Where("slug = ? AND parent_id = ? AND is_active = ?", subCategory, parent.ID, true).First(&child).Error
+	if err != nil {
+		return ErrUnknownSubCategory
+	}
+
+	return nil
+}
+
+// CategoryTree laadt de categorieboom met namen in de gevraagde taal.
+// Zonder includeInactive worden inactieve categorieën (en hun kinderen) weggelaten.
+func CategoryTree(lang string, includeInactive bool) ([]models.Category, error) {
+	query := config.DB.Order("sort_order, slug")
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var categories []models.Category
+	if err := query.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	slugs := make([]string, len(categories))
+	for i, category := range categories {
+		slugs[i] = category.Slug
+	}
+
+	names, err := CategoryNames(slugs, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	var translations []models.CategoryTranslation
+	if err := config.DB.Where("slug IN ?", slugs).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	allNames := map[string]map[string]string{}
+	for _, t := range translations {
+		if allNames[t.Slug] == nil {
+			allNames[t.Slug] = map[string]string{}
+		}
+		allNames[t.Slug][t.Language] = t.Name
+	}
+
+	children := map[uint][]models.Category{}
+	var roots []models.Category
+	for _, category := range categories {
+		category.Name = names[category.Slug]
+		category.Names = allNames[category.Slug]
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	tree := make([]models.Category, 0, len(roots))
+	for _, root := range roots {
+		root.Children = children[root.ID]
+		tree = append(tree, root)
+	}
+
+	return tree, nil
+}
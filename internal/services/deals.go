@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCustomerNotActive = errors.New("deals are only available for businesses with an active customer")
+	ErrDealNotAvailable  = errors.New("deal is not available")
+	ErrDealSoldOut       = errors.New("deal has reached its redemption limit")
+	ErrDealVisitorLimit  = errors.New("visitor has reached the redemption limit for this deal")
+	ErrDealLoginRequired = errors.New("login required to redeem this deal")
+)
+
+// dealCodeAlphabet - zonder 0/O en 1/I, makkelijk voor te lezen aan de kassa
+const dealCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// dealRedemptionCountSQL - aantal inwisselingen per deal
+const dealRedemptionCountSQL = "(SELECT COUNT(*) FROM deal_redemptions r WHERE r.deal_id = deals.id)"
+
+// BusinessHasActiveCustomer - alleen betalende klanten mogen deals plaatsen
+func BusinessHasActiveCustomer(business *models.Business) (bool, error) {
+	if business.CustomerID == nil {
+		return false, nil
+	}
+
+	var count int64
+	err := config.DB.Model(&models.Customer{}).
+		Where("id = ? AND status = ?", *business.CustomerID, models.StatusActive).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CurrentDeals - deals die nu publiek zichtbaar zijn: actief, binnen de looptijd,
+// niet uitverkocht en van een actief bedrijf met een actieve klant
+func CurrentDeals(db *gorm.DB, now time.Time) *gorm.DB {
+	return db.Model(&models.Deal{}).
+		Select("deals.*"). // Zonder Select neemt GORM bij joins ook redemption_count als kolom
+		Joins("JOIN businesses ON businesses.id = deals.business_id").
+		Joins("JOIN customers ON customers.id = businesses.customer_id").
+		Where("deals.is_active = ? AND deals.starts_at <= ? AND deals.ends_at > ?", true, now, now).
+		Where("businesses.is_active = ? AND customers.status = ?", true, models.StatusActive).
+		Where("(deals.max_total = 0 OR " + dealRedemptionCountSQL + " < deals.max_total)")
+}
+
+// PreloadCurrentDeals - voor Preload("Deals"): zelfde voorwaarden als CurrentDeals voor
+// de deal zelf (bedrijf en klant controleert de aanroeper)
+func PreloadCurrentDeals(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("deals.is_active = ? AND deals.starts_at <= ? AND deals.ends_at > ?", true, now, now).
+			Where("(deals.max_total = 0 OR " + dealRedemptionCountSQL + " < deals.max_total)").
+			Order("deals.ends_at, deals.id")
+	}
+}
+
+// HideDealCodes - de vaste code krijgt een bezoeker pas bij het inwisselen
+func HideDealCodes(deals []models.Deal) {
+	for i := range deals {
+		deals[i].Code = ""
+	}
+}
+
+// WithRedemptionCount selecteert het aantal inwisselingen mee
+func WithRedemptionCount(db *gorm.DB) *gorm.DB {
+	return db.Select("deals.*, " + dealRedemptionCountSQL + " AS redemption_count")
+}
+
+// HashVisitor - bezoeker ID's worden niet letterlijk opgeslagen
+func HashVisitor(visitorID string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(visitorID)))
+	return hex.EncodeToString(sum[:])
+}
+
+// newDealCode - "XXXX-XXXX"
+func newDealCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, 0, 9)
+	for i, b := range buf {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		code = append(code, dealCodeAlphabet[int(b)%len(dealCodeAlphabet)])
+	}
+	return string(code), nil
+}
+
+// DealRedemptionResult - wat de bezoeker terugkrijgt
+type DealRedemptionResult struct {
+	Redemption models.DealRedemption `json:"redemption"`
+	Code       string                `json:"code,omitempty"`
+	Remaining  *int                  `json:"remaining,omitempty"` // Nog te gebruiken door deze bezoeker (leeg = onbeperkt)
+}
+
+// RedeemDeal wisselt een deal in voor een bezoeker. De deal wordt gelockt zodat
+// de limieten ook bij gelijktijdige verzoeken kloppen. Een deal met een limiet per
+// bezoeker vereist een login (userID) en telt per gebruiker: het visitor_id komt van
+// de client en een IP adres is te vervalsen of gedeeld (NAT), dus geen van beide is
+// een betrouwbare identiteit.
+func RedeemDeal(ctx context.Context, dealID uint, visitorID string, userID *uint) (*DealRedemptionResult, error) {
+	visitorHash := HashVisitor(visitorID)
+	clientHash := ""
+	if userID != nil {
+		clientHash = HashVisitor("user:" + strconv.FormatUint(uint64(*userID), 10))
+	}
+	var result DealRedemptionResult
+
+	err := config.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deal models.Deal
+		err := CurrentDeals(tx, time.Now()).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "deals"}}).
+			Where("deals.id = ?", dealID).
+			First(&deal).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDealNotAvailable
+		}
+		if err != nil {
+			return err
+		}
+
+		if deal.MaxTotal > 0 {
+			var total int64
+			if err := tx.Model(&models.DealRedemption{}).Where("deal_id = ?", deal.ID).Count(&total).Error; err != nil {
+				return err
+			}
+			if total >= int64(deal.MaxTotal) {
+				return ErrDealSoldOut
+			}
+		}
+
+		if deal.MaxPerVisitor > 0 {
+			if clientHash == "" {
+				return ErrDealLoginRequired
+			}
+			var used int64
+			if err := tx.Model(&models.DealRedemption{}).
+				Where("deal_id = ? AND client_hash = ?", deal.ID, clientHash).
+				Count(&used).Error; err != nil {
+				return err
+			}
+			if used >= int64(deal.MaxPerVisitor) {
+				return ErrDealVisitorLimit
+			}
+			remaining := deal.MaxPerVisitor - int(used) - 1
+			result.Remaining = &remaining
+		}
+
+		redemption := models.DealRedemption{DealID: deal.ID, VisitorHash: visitorHash, ClientHash: clientHash}
+		switch deal.CodeMode {
+		case models.DealCodeShared:
+			result.Code = deal.Code
+		case models.DealCodeUnique:
+			code, err := newDealCode()
+			if err != nil {
+				return err
+			}
+			redemption.Code = code
+			result.Code = code
+		}
+
+		if err := tx.Create(&redemption).Error; err != nil {
+			return err
+		}
+		result.Redemption = redemption
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DealReportDay - inwisselingen per dag (Duitse tijd)
+type DealReportDay struct {
+	Day         string `json:"day"`
+	Redemptions int64  `json:"redemptions"`
+}
+
+// DealReport - inwisselingen van één deal in een periode
+type DealReport struct {
+	DealID      uint            `json:"deal_id"`
+	Title       string          `json:"title"`
+	StartsAt    time.Time       `json:"starts_at"`
+	EndsAt      time.Time       `json:"ends_at"`
+	Redemptions int64           `json:"redemptions"`
+	Visitors    int64           `json:"visitors"` // Unieke bezoekers
+	Days        []DealReportDay `json:"days"`
+}
+
+// BusinessDealReport - rapportage voor alle deals van een bedrijf tussen from en to
+func BusinessDealReport(ctx context.Context, businessID uint, from, to time.Time) ([]DealReport, error) {
+	db := config.DB.WithContext(ctx)
+
+	var deals []models.Deal
+	if err := db.Where("business_id = ? AND starts_at < ? AND ends_at > ?", businessID, to, from).
+		Order("starts_at, id").Find(&deals).Error; err != nil {
+		return nil, err
+	}
+
+	reports := make([]DealReport, 0, len(deals))
+	if len(deals) == 0 {
+		return reports, nil
+	}
+
+	ids := make([]uint, len(deals))
+	for i, deal := range deals {
+		ids[i] = deal.ID
+	}
+
+	var totals []struct {
+		DealID      uint
+		Redemptions int64
+		Visitors    int64
+	}
+	err := db.Model(&models.DealRedemption{}).
+		Select("deal_id, COUNT(*) AS redemptions, COUNT(DISTINCT visitor_hash) AS visitors").
+		Where("deal_id IN ? AND created_at >= ? AND created_at < ?", ids, from, to).
+		Group("deal_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var days []struct {
+		DealID      uint
+		Day         string
+		Redemptions int64
+	}
+	err = db.Model(&models.DealRedemption{}).
+		Select("deal_id, to_char(created_at AT TIME ZONE ?, 'YYYY-MM-DD') AS day, COUNT(*) AS redemptions", BerlinLocation.String()).
+		Where("deal_id IN ? AND created_at >= ? AND created_at < ?", ids, from, to).
+		Group("deal_id, day").
+		Order("day").
+		Scan(&days).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byDeal := make(map[uint]*DealReport, len(deals))
+	for _, deal := range deals {
+		reports = append(reports, DealReport{
+			DealID:   deal.ID,
+			Title:    deal.Title,
+			StartsAt: deal.StartsAt,
+			EndsAt:   deal.EndsAt,
+			Days:     []DealReportDay{},
+		})
+	}
+	for i := range reports {
+		byDeal[reports[i].DealID] = &reports[i]
+	}
+
+	for _, total := range totals {
+		byDeal[total.DealID].Redemptions = total.Redemptions
+		byDeal[total.DealID].Visitors = total.Visitors
+	}
+	for _, day := range days {
+		byDeal[day.DealID].Days = append(byDeal[day.DealID].Days, DealReportDay{Day: day.Day, Redemptions: day.Redemptions})
+	}
+
+	return reports, nil
+}
+
+// ValidateDealWindow - looptijd controleren (maximaal een jaar)
+func ValidateDealWindow(startsAt, endsAt time.Time) error {
+	if !endsAt.After(startsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if endsAt.Sub(startsAt) > 366*24*time.Hour {
+		return errors.New("a deal can run for at most 366 days")
+	}
+	return nil
+}
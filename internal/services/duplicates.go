@@ -0,0 +1,314 @@
+package services
+
+import (
+	"math"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/models"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Zoekgrenzen voor kandidaatparen
+const (
+	duplicateRadiusMeters = 150
+	duplicateMaxPairs     = 5000
+)
+
+// Gewichten van de signalen; ontbrekende signalen (geen adres, telefoon of
+// coördinaten aan één kant) tellen niet mee
+var duplicateWeights = map[string]float64{
+	"name":     0.40,
+	"address":  0.25,
+	"phone":    0.20,
+	"distance": 0.15,
+}
+
+// Woorden die niets zeggen over welk bedrijf het is
+var nameStopwords = map[string]bool{
+	"gmbh": true, "co": true, "kg": true, "ohg": true, "ek": true, "ug": true, "ag": true, "mbh": true,
+	"und": true, "en": true, "the": true, "der": true, "die": true, "das": true, "de": true, "het": true,
+	"supermarkt": true, "markt": true, "restaurant": true, "tankstelle": true, "tankstation": true,
+}
+
+// DuplicateCandidate - mogelijk dubbel paar met de deelscores
+type DuplicateCandidate struct {
+	BusinessA models.Business    `json:"business_a"`
+	BusinessB models.Business    `json:"business_b"`
+	Score     float64            `json:"score"`
+	Signals   map[string]float64 `json:"signals"` // 0..1 per signaal
+	DistanceM *float64           `json:"distance_m,omitempty"`
+}
+
+// NormalizeBusinessName - kleine letters, zonder accenten, rechtsvormen en generieke woorden
+func NormalizeBusinessName(name string) string {
+	var words []string
+	for _, word := range normalizeWords(name) {
+		if !nameStopwords[word] {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// normalizeAddress - "Hauptstraße 5" en "Hauptstr. 5" worden gelijk
+func normalizeAddress(address string) string {
+	words := normalizeWords(address)
+	for i, word := range words {
+		for _, suffix := range []string{"strasse", "straat"} {
+			if strings.HasSuffix(word, suffix) {
+				words[i] = strings.TrimSuffix(word, suffix) + "str"
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// normalizeWords - woorden van letters en cijfers, umlauts uitgeschreven
+func normalizeWords(value string) []string {
+	replacer := strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss", "é", "e", "è", "e", "ë", "e")
+	value = replacer.Replace(strings.ToLower(value))
+
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalizePhone - laatste 9 cijfers, zodat +49 211 ... en 0211 ... gelijk zijn
+func normalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	value := digits.String()
+	if len(value) < 6 {
+		return ""
+	}
+	if len(value) > 9 {
+		value = value[len(value)-9:]
+	}
+	return value
+}
+
+// trigrams - zoals pg_trgm: elk woord met twee spaties ervoor en één erna
+func trigrams(value string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(value) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// TrigramSimilarity - gedeelde trigrams / alle trigrams (0..1)
+func TrigramSimilarity(a, b string) float64 {
+	setA, setB := trigrams(a), trigrams(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range setA {
+		if setB[trigram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(setA)+len(setB)-shared)
+}
+
+// nameScore - trigram gelijkenis; bevat de ene naam alle woorden van de andere
+// ("rewe" en "rewe city") dan minstens 0.8
+func nameScore(a, b string) float64 {
+	score := TrigramSimilarity(a, b)
+
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+	if len(wordsA) > 0 {
+		contained := true
+		for _, word := range wordsA {
+			found := false
+			for _, other := range wordsB {
+				if word == other {
+					found = true
+					break
+				}
+			}
+			contained = contained && found
+		}
+		if contained {
+			score = math.Max(score, 0.8)
+		}
+	}
+
+	return score
+}
+
+func hasLocation(b *models.Business) bool {
+	return b.Latitude != 0 || b.Longitude != 0
+}
+
+// ScoreDuplicate - gewogen score (0..1) van twee bedrijven
+func ScoreDuplicate(a, b *models.Business) DuplicateCandidate {
+	candidate := DuplicateCandidate{BusinessA: *a, BusinessB: *b, Signals: map[string]float64{}}
+
+	nameA, nameB := NormalizeBusinessName(a.Name), NormalizeBusinessName(b.Name)
+	if nameA == "" || nameB == "" { // Alleen generieke woorden: dan de volledige naam
+		nameA, nameB = strings.Join(normalizeWords(a.Name), " "), strings.Join(normalizeWords(b.Name), " ")
+	}
+	candidate.Signals["name"] = nameScore(nameA, nameB)
+
+	if a.Address != "" && b.Address != "" {
+		candidate.Signals["address"] = TrigramSimilarity(normalizeAddress(a.Address), normalizeAddress(b.Address))
+	}
+
+	if phoneA, phoneB := normalizePhone(a.Phone), normalizePhone(b.Phone); phoneA != "" && phoneB != "" {
+		candidate.Signals["phone"] = 0
+		if phoneA == phoneB {
+			candidate.Signals["phone"] = 1
+		}
+	}
+
+	if hasLocation(a) && hasLocation(b) {
+		meters := geo.DistanceKm(geo.Point{Lat: a.Latitude, Lng: a.Longitude}, geo.Point{Lat: b.Latitude, Lng: b.Longitude}) * 1000
+		candidate.DistanceM = &meters
+		// 1 tot 30 m, daarna lineair naar 0 bij 300 m
+		candidate.Signals["distance"] = math.Max(0, math.Min(1, (300-meters)/270))
+	}
+
+	var total, weights float64
+	for signal, value := range candidate.Signals {
+		total += duplicateWeights[signal] * value
+		weights += duplicateWeights[signal]
+	}
+	candidate.Score = math.Round(total/weights*1000) / 1000
+
+	return candidate
+}
+
+// duplicatePairsSQL - kandidaatparen: dichtbij (zelfde categorie of lijkende naam),
+// zelfde postcode met lijkende naam, of hetzelfde telefoonnummer. De paren worden
+// eerst gerangschikt met dezelfde signalen en gewichten als ScoreDuplicate (de naam
+// via similarity/word_similarity, zodat "rewe" en "rewe supermarkt" hoog eindigen),
+// zodat @max_pairs de beste paren overhoudt en niet een willekeurige greep.
+const duplicatePairsSQL = `
+SELECT pairs.a_id, pairs.b_id FROM (
+	SELECT a.id AS a_id, b.id AS b_id
+	FROM businesses a
+	JOIN businesses b ON a.id < b.id
+		AND earth_box(ll_to_earth(a.latitude, a.longitude), @radius) @> ll_to_earth(b.latitude, b.longitude)
+		AND (a.category = b.category OR search_normalize(a.name) % search_normalize(b.name))
+	WHERE a.merged_into_id IS NULL AND b.merged_into_id IS NULL
+		AND NOT (a.latitude = 0 AND a.longitude = 0) AND NOT (b.latitude = 0 AND b.longitude = 0)
+	UNION
+	SELECT a.id, b.id
+	FROM businesses a
+	JOIN businesses b ON a.id < b.id AND a.postal_code = b.postal_code
+		AND search_normalize(a.name) % search_normalize(b.name)
+	WHERE a.postal_code <> '' AND a.merged_into_id IS NULL AND b.merged_into_id IS NULL
+	UNION
+	SELECT a.id, b.id
+	FROM (SELECT id, right(regexp_replace(phone, '\D', '', 'g'), 9) AS phone_key FROM businesses
+		WHERE merged_into_id IS NULL AND length(regexp_replace(phone, '\D', '', 'g')) >= 6) a
+	JOIN (SELECT id, right(regexp_replace(phone, '\D', '', 'g'), 9) AS phone_key FROM businesses
+		WHERE merged_into_id IS NULL AND length(regexp_replace(phone, '\D', '', 'g')) >= 6) b
+		ON a.id < b.id AND a.phone_key = b.phone_key
+) pairs
+JOIN businesses a ON a.id = pairs.a_id
+JOIN businesses b ON b.id = pairs.b_id
+CROSS JOIN LATERAL (SELECT
+	greatest(similarity(search_normalize(a.name), search_normalize(b.name)),
+		word_similarity(search_normalize(a.name), search_normalize(b.name)),
+		word_similarity(search_normalize(b.name), search_normalize(a.name))) AS name,
+	CASE WHEN a.address <> '' AND b.address <> ''
+		THEN similarity(search_normalize(a.address), search_normalize(b.address)) END AS address,
+	CASE WHEN length(regexp_replace(a.phone, '\D', '', 'g')) >= 6 AND length(regexp_replace(b.phone, '\D', '', 'g')) >= 6
+		THEN (right(regexp_replace(a.phone, '\D', '', 'g'), 9) = right(regexp_replace(b.phone, '\D', '', 'g'), 9))::int END AS phone,
+	CASE WHEN NOT (a.latitude = 0 AND a.longitude = 0) AND NOT (b.latitude = 0 AND b.longitude = 0)
+		THEN greatest(0, least(1, (300 - earth_distance(ll_to_earth(a.latitude, a.longitude), ll_to_earth(b.latitude, b.longitude))) / 270)) END AS distance
+) signal
+WHERE (@business_id = 0 OR pairs.a_id = @business_id OR pairs.b_id = @business_id)
+	AND NOT EXISTS (
+		SELECT 1 FROM duplicate_dismissals d WHERE d.business_a_id = pairs.a_id AND d.business_b_id = pairs.b_id
+	)
+ORDER BY (CAST(@w_name AS float8) * signal.name
+		+ coalesce(CAST(@w_address AS float8) * signal.address, 0)
+		+ coalesce(CAST(@w_phone AS float8) * signal.phone, 0)
+		+ coalesce(CAST(@w_distance AS float8) * signal.distance, 0))
+	/ (CAST(@w_name AS float8)
+		+ CASE WHEN signal.address IS NULL THEN 0 ELSE CAST(@w_address AS float8) END
+		+ CASE WHEN signal.phone IS NULL THEN 0 ELSE CAST(@w_phone AS float8) END
+		+ CASE WHEN signal.distance IS NULL THEN 0 ELSE CAST(@w_distance AS float8) END) DESC,
+	pairs.a_id, pairs.b_id
+LIMIT @max_pairs`
+
+// FindDuplicates zoekt mogelijke dubbele bedrijven, hoogste score eerst.
+// businessID > 0 beperkt de zoektocht tot paren met dat bedrijf.
+func FindDuplicates(minScore float64, limit int, businessID uint) ([]DuplicateCandidate, error) {
+	type pair struct {
+		AID uint
+		BID uint
+	}
+
+	var pairs []pair
+	err := config.DB.Raw(duplicatePairsSQL, map[string]interface{}{
+		"radius":      duplicateRadiusMeters,
+		"business_id": businessID,
+		"max_pairs":   duplicateMaxPairs,
+		"w_name":      duplicateWeights["name"],
+		"w_address":   duplicateWeights["address"],
+		"w_phone":     duplicateWeights["phone"],
+		"w_distance":  duplicateWeights["distance"],
+	}).Scan(&pairs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return []DuplicateCandidate{}, nil
+	}
+
+	idSet := map[uint]bool{}
+	for _, p := range pairs {
+		idSet[p.AID] = true
+		idSet[p.BID] = true
+	}
+	ids := make([]uint, 0, len(idSet))
+	for id := range idSet {
+		ids = append(ids, id)
+	}
+
+	var businesses []models.Business
+	if err := config.DB.Where("id IN ?", ids).Find(&businesses).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Business, len(businesses))
+	for i := range businesses {
+		byID[businesses[i].ID] = &businesses[i]
+	}
+
+	candidates := []DuplicateCandidate{}
+	for _, p := range pairs {
+		a, b := byID[p.AID], byID[p.BID]
+		if a == nil || b == nil {
+			continue
+		}
+		if candidate := ScoreDuplicate(a, b); candidate.Score >= minScore {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+
+	"projectpeterperplexity/internal/models"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"+49 211 1234567", "111234567"},
+		{"0211 1234567", "111234567"},
+		{"0049 (211) 123-45-67", "111234567"},
+		{"+31 (0)53 123 45 67", "531234567"},
+		{"053-1234567", "531234567"},
+		{"123456", "123456"},
+		{"12345", ""}, // Te kort om iets te zeggen
+		{"geen nummer", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizePhone(tt.phone); got != tt.want {
+			t.Errorf("normalizePhone(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestNameScore(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		min, max float64
+	}{
+		{"rewe", "rewe", 1, 1},
+		{"rewe", "rewe city", 0.8, 0.8},
+		{"rewe city", "rewe", 0.8, 0.8},
+		{"cafe mueller", "mueller cafe", 1, 1},
+		{"edeka center", "edeka centrum", 0.4, 0.8},
+		{"aldi", "lidl", 0, 0.3},
+		{"rewe", "penny", 0, 0},
+		{"", "rewe", 0, 0},
+	}
+	for _, tt := range tests {
+		if got := nameScore(tt.a, tt.b); got < tt.min || got > tt.max {
+			t.Errorf("nameScore(%q, %q) = %.3f, want %.2f..%.2f", tt.a, tt.b, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestScoreDuplicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     models.Business
+		min, max float64
+	}{
+		{
+			name: "REWE Supermarkt en REWE op hetzelfde adres",
+			a:    models.Business{Name: "REWE Supermarkt", Address: "Hauptstraße 5", Phone: "+49 2821 123456", Latitude: 51.7890, Longitude: 6.1380},
+			b:    models.Business{Name: "REWE", Address: "Hauptstr. 5", Phone: "02821 123456", Latitude: 51.7891, Longitude: 6.1381},
+			min:  0.95, max: 1,
+		},
+		{
+			name: "REWE Supermarkt en REWE alleen op naam",
+			a:    models.Business{Name: "REWE Supermarkt"},
+			b:    models.Business{Name: "REWE"},
+			min:  1, max: 1,
+		},
+		{
+			name: "umlauts en accenten",
+			a:    models.Business{Name: "Café Müller GmbH"},
+			b:    models.Business{Name: "Cafe Mueller"},
+			min:  1, max: 1,
+		},
+		{
+			name: "alleen generieke woorden",
+			a:    models.Business{Name: "Restaurant"},
+			b:    models.Business{Name: "Restaurant GmbH"},
+			min:  0.8, max: 0.8,
+		},
+		{
+			name: "zelfde telefoon, andere naam",
+			a:    models.Business{Name: "Bäckerei Schmitz", Phone: "+49 2821 987654"},
+			b:    models.Business{Name: "Schmitz Backstube", Phone: "02821 987654"},
+			min:  0.5, max: 0.9,
+		},
+		{
+			name: "buren met een andere naam",
+			a:    models.Business{Name: "Aldi Süd", Address: "Bahnhofstraße 1", Phone: "02821 111111", Latitude: 51.7890, Longitude: 6.1380},
+			b:    models.Business{Name: "Lidl", Address: "Bahnhofstraße 3", Phone: "02821 222222", Latitude: 51.7900, Longitude: 6.1390},
+			min:  0, max: 0.4,
+		},
+		{
+			name: "zelfde naam, ver weg",
+			a:    models.Business{Name: "Edeka", Latitude: 51.7890, Longitude: 6.1380},
+			b:    models.Business{Name: "Edeka", Latitude: 51.8500, Longitude: 6.2500},
+			min:  0.7, max: 0.75,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidate := ScoreDuplicate(&tt.a, &tt.b)
+			if candidate.Score < tt.min || candidate.Score > tt.max {
+				t.Fatalf("score %.3f, want %.2f..%.2f (signals %v)", candidate.Score, tt.min, tt.max, candidate.Signals)
+			}
+			if reverse := ScoreDuplicate(&tt.b, &tt.a); reverse.Score != candidate.Score {
+				t.Fatalf("score not symmetric: %.3f vs %.3f", candidate.Score, reverse.Score)
+			}
+		})
+	}
+}
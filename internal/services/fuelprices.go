@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FuelPriceMaxAge - prijzen die langer niet bevestigd zijn tellen niet mee bij "goedkoopste in de buurt"
+const FuelPriceMaxAge = 48 * time.Hour
+
+// FuelFeed - prijzen per Tankerkönig station ID en brandstofsoort
+type FuelFeed map[string]map[string]float64
+
+// tankerkoenigPayload - antwoorden van prices.php, list.php en detail.php
+type tankerkoenigPayload struct {
+	OK       *bool                             `json:"ok"`
+	Message  string                            `json:"message"`
+	Prices   map[string]map[string]interface{} `json:"prices"`   // prices.php: {"<id>": {"status": "open", "e5": 1.789, ...}}
+	Stations []map[string]interface{}          `json:"stations"` // list.php (type=all)
+	Station  map[string]interface{}            `json:"station"`  // detail.php
+}
+
+// ParseTankerkoenig leest een Tankerkönig-compatibel antwoord. Soorten die een
+// station niet heeft (false of null) worden overgeslagen; "lpg" mag als extra veld.
+func ParseTankerkoenig(data []byte) (FuelFeed, error) {
+	var payload tankerkoenigPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	if payload.OK != nil && !*payload.OK {
+		return nil, errors.New("feed reports an error: " + payload.Message)
+	}
+
+	feed := FuelFeed{}
+	for id, station := range payload.Prices {
+		feed.add(id, station)
+	}
+	for _, station := range payload.Stations {
+		id, _ := station["id"].(string)
+		feed.add(id, station)
+	}
+	if payload.Station != nil {
+		id, _ := payload.Station["id"].(string)
+		feed.add(id, payload.Station)
+	}
+
+	if len(feed) == 0 {
+		return nil, errors.New("no prices found (expected prices, stations or station)")
+	}
+
+	return feed, nil
+}
+
+func (feed FuelFeed) add(id string, station map[string]interface{}) {
+	if id == "" {
+		return
+	}
+	for _, fuel := range models.FuelTypes {
+		price, ok := station[fuel].(float64)
+		if !ok || price <= 0 || price >= 10 {
+			continue
+		}
+		if feed[id] == nil {
+			feed[id] = map[string]float64{}
+		}
+		feed[id][fuel] = price
+	}
+}
+
+// FuelIngestResult - resultaat van een aanlevering
+type FuelIngestResult struct {
+	Stations  int      `json:"stations"`
+	Matched   int      `json:"matched"`
+	Recorded  int      `json:"recorded"`  // Nieuwe of gewijzigde prijzen
+	Unchanged int      `json:"unchanged"` // Zelfde prijs als de vorige keer
+	Unmatched []string `json:"unmatched"` // Station IDs zonder bedrijf (fuel_station_id)
+}
+
+// IngestFuelPrices slaat de prijzen op voor de bedrijven met een bekend station ID.
+// Een prijs die gelijk is aan de laatste bekende prijs wordt niet opnieuw opgeslagen;
+// die rij krijgt alleen een nieuwe last_confirmed_at.
+func IngestFuelPrices(ctx context.Context, feed FuelFeed, source string, observedAt time.Time) (FuelIngestResult, error) {
+	result := FuelIngestResult{Stations: len(feed), Unmatched: []string{}}
+
+	stationIDs := make([]string, 0, len(feed))
+	for id := range feed {
+		stationIDs = append(stationIDs, id)
+	}
+	sort.Strings(stationIDs)
+
+	db := config.DB.WithContext(ctx)
+
+	var businesses []models.Business
+	if err := db.Select("id", "fuel_station_id").Where("fuel_station_id IN ?", stationIDs).Find(&businesses).Error; err != nil {
+		return result, err
+	}
+	byStation := make(map[string]uint, len(businesses))
+	businessIDs := make([]uint, 0, len(businesses))
+	for _, business := range businesses {
+		byStation[*business.FuelStationID] = business.ID
+		businessIDs = append(businessIDs, business.ID)
+	}
+
+	latest, err := LatestFuelPrices(ctx, businessIDs)
+	if err != nil {
+		return result, err
+	}
+	current := map[uint]map[string]models.FuelPrice{}
+	for _, price := range latest {
+		if current[price.BusinessID] == nil {
+			current[price.BusinessID] = map[string]models.FuelPrice{}
+		}
+		current[price.BusinessID][price.FuelType] = price
+	}
+
+	var records []models.FuelPrice
+	var confirmed []uint
+	for _, id := range stationIDs {
+		businessID, ok := byStation[id]
+		if !ok {
+			result.Unmatched = append(result.Unmatched, id)
+			continue
+		}
+		result.Matched++
+
+		for fuel, price := range feed[id] {
+			if previous, ok := current[businessID][fuel]; ok && math.Abs(previous.Price-price) < 0.0005 {
+				result.Unchanged++
+				confirmed = append(confirmed, previous.ID)
+				continue
+			}
+			records = append(records, models.FuelPrice{
+				BusinessID:      businessID,
+				FuelType:        fuel,
+				Price:           price,
+				ObservedAt:      observedAt,
+				LastConfirmedAt: observedAt,
+				Source:          source,
+			})
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if len(records) > 0 {
+			if err := tx.CreateInBatches(&records, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(confirmed) > 0 {
+			// GREATEST: een late aanlevering met oude data maakt een prijs niet ouder
+			return tx.Model(&models.FuelPrice{}).Where("id IN ?", confirmed).
+				UpdateColumn("last_confirmed_at", gorm.Expr("GREATEST(last_confirmed_at, ?)", observedAt)).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Recorded = len(records)
+
+	return result, nil
+}
+
+// LatestFuelPrices - laatste prijs per bedrijf en brandstofsoort
+func LatestFuelPrices(ctx context.Context, businessIDs []uint) ([]models.FuelPrice, error) {
+	prices := []models.FuelPrice{}
+	if len(businessIDs) == 0 {
+		return prices, nil
+	}
+
+	err := config.DB.WithContext(ctx).
+		Select("DISTINCT ON (business_id, fuel_type) *").
+		Where("business_id IN ?", businessIDs).
+		Order("business_id, fuel_type, observed_at DESC").
+		Find(&prices).Error
+
+	return prices, err
+}
+
+// FuelReferencePrices - Nederlandse referentieprijs per brandstofsoort
+func FuelReferencePrices(ctx context.Context) (map[string]float64, error) {
+	var references []models.FuelReferencePrice
+	if err := config.DB.WithContext(ctx).Find(&references).Error; err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]float64, len(references))
+	for _, reference := range references {
+		prices[reference.FuelType] = reference.Price
+	}
+	return prices, nil
+}
+
+// FuelOffer - actuele prijs bij een station in de buurt
+type FuelOffer struct {
+	Business        models.Business `json:"business"`
+	FuelType        string          `json:"fuel_type"`
+	Price           float64         `json:"price"`
+	ObservedAt      time.Time       `json:"observed_at"`       // Sinds wanneer deze prijs geldt
+	LastConfirmedAt time.Time       `json:"last_confirmed_at"` // Laatste aanlevering met deze prijs
+	DistanceKm      float64         `json:"distance_km"`
+	SavingsPerLiter *float64        `json:"savings_per_liter,omitempty"` // T.o.v. de Nederlandse referentieprijs
+	SavingsPerTank  *float64        `json:"savings_per_tank,omitempty"`
+}
+
+// fuelOfferRow - prijs en afstand uit de database
+type fuelOfferRow struct {
+	BusinessID      uint
+	Price           float64
+	ObservedAt      time.Time
+	LastConfirmedAt time.Time
+	DistanceKm      float64
+}
+
+// CheapestFuel - goedkoopste actieve stations binnen radiusKm van origin (recente prijzen)
+func CheapestFuel(ctx context.Context, fuel string, origin geo.Point, radiusKm float64, limit int) ([]FuelOffer, error) {
+	db := config.DB.WithContext(ctx)
+
+	latest := db.Model(&models.FuelPrice{}).
+		Select("DISTINCT ON (business_id) business_id, price, observed_at, last_confirmed_at").
+		Where("fuel_type = ? AND last_confirmed_at >= ?", fuel, time.Now().Add(-FuelPriceMaxAge)).
+		Order("business_id, observed_at DESC")
+
+	radiusMeters := radiusKm * 1000
+	var rows []fuelOfferRow
+	err := db.Table("(?) AS latest", latest).
+		Select("latest.business_id, latest.price, latest.observed_at, latest.last_confirmed_at, "+
+			"earth_distance(ll_to_earth(?, ?), ll_to_earth(businesses.latitude, businesses.longitude)) / 1000 AS distance_km",
+			origin.Lat, origin.Lng).
+		Joins("JOIN businesses ON businesses.id = latest.business_id").
+		Where("businesses.is_active = ?", true).
+		Where("earth_box(ll_to_earth(?, ?), ?) @> ll_to_earth(businesses.latitude, businesses.longitude)",
+			origin.Lat, origin.Lng, radiusMeters).
+		Where("earth_distance(ll_to_earth(?, ?), ll_to_earth(businesses.latitude, businesses.longitude)) <= ?",
+			origin.Lat, origin.Lng, radiusMeters).
+		Order("latest.price, distance_km").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]FuelOffer, 0, len(rows))
+	if len(rows) == 0 {
+		return offers, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.BusinessID
+	}
+	var businesses []models.Business
+	if err := WithListingTier(db).Where("id IN ?", ids).Find(&businesses).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Business, len(businesses))
+	for _, business := range businesses {
+		byID[business.ID] = business
+	}
+
+	for _, row := range rows {
+		offers = append(offers, FuelOffer{
+			Business:        byID[row.BusinessID],
+			FuelType:        fuel,
+			Price:           row.Price,
+			ObservedAt:      row.ObservedAt,
+			LastConfirmedAt: row.LastConfirmedAt,
+			DistanceKm:      round1(row.DistanceKm),
+		})
+	}
+
+	return offers, nil
+}
+
+// ApplyFuelSavings vult de besparing t.o.v. de referentieprijs (per liter en per tank)
+func ApplyFuelSavings(offers []FuelOffer, reference float64, tankLiters float64) {
+	for i := range offers {
+		perLiter := math.Round((reference-offers[i].Price)*1000) / 1000
+		perTank := math.Round(perLiter*tankLiters*100) / 100
+		offers[i].SavingsPerLiter = &perLiter
+		offers[i].SavingsPerTank = &perTank
+	}
+}
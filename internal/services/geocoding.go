@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/geocoding"
+	"projectpeterperplexity/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// maxLocationDistanceKm - toegestane afstand tot het midden van de postcode,
+// bovenop de spreiding van het postcodegebied zelf
+const maxLocationDistanceKm = 10
+
+// GeocodeBusiness vult ontbrekende coördinaten (0,0) met het midden van de postcode
+// en controleert ingevulde coördinaten. Het resultaat staat in GeocodeStatus.
+// Zonder geocoder (geen dataset geladen) verandert er niets.
+func GeocodeBusiness(ctx context.Context, business *models.Business) error {
+	if geocoding.Default == nil {
+		return nil
+	}
+
+	result, err := geocoding.Default.Geocode(ctx, geocoding.Address{
+		Street:     business.Address,
+		PostalCode: business.PostalCode,
+		City:       business.City,
+		Country:    business.Country,
+	})
+	if errors.Is(err, geocoding.ErrNotFound) {
+		business.GeocodeStatus = models.GeocodeUnknownPostcode
+		business.GeocodeDistanceKm = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	point := geo.Point{Lat: business.Latitude, Lng: business.Longitude}
+	if point.IsZero() {
+		business.Latitude = result.Point.Lat
+		business.Longitude = result.Point.Lng
+		business.GeocodeStatus = models.GeocodeFilled
+		business.GeocodeDistanceKm = nil
+		return nil
+	}
+
+	distance := geo.DistanceKm(point, result.Point)
+	business.GeocodeDistanceKm = &distance
+	if distance > maxLocationDistanceKm+result.RadiusKm {
+		business.GeocodeStatus = models.GeocodeMismatch
+	} else {
+		business.GeocodeStatus = models.GeocodeOK
+	}
+
+	return nil
+}
+
+// CheckBusinessLocations controleert alle (niet samengevoegde) bedrijven opnieuw,
+// bijvoorbeeld na het laden van een nieuwe dataset. Geeft het aantal per status.
+func CheckBusinessLocations(ctx context.Context) (map[string]int, error) {
+	if geocoding.Default == nil {
+		return nil, errors.New("geocoding is not configured (GEOCODING_POSTCODES)")
+	}
+
+	counts := map[string]int{}
+	var businesses []models.Business
+
+	err := config.DB.WithContext(ctx).Where("merged_into_id IS NULL").
+		FindInBatches(&businesses, 500, func(tx *gorm.DB, batch int) error {
+			for i := range businesses {
+				business := &businesses[i]
+				if err := GeocodeBusiness(ctx, business); err != nil {
+					return err
+				}
+				counts[business.GeocodeStatus]++
+
+				err := config.DB.WithContext(ctx).Model(business).
+					Select("latitude", "longitude", "geocode_status", "geocode_distance_km").
+					UpdateColumns(business).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+
+	return counts, err
+}
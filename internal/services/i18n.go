@@ -0,0 +1,178 @@
+package services
+
+import (
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage - de Nederlandse site is de standaard
+const DefaultLanguage = "nl"
+
+// SupportedLanguages - talen waarin content vertaald kan worden
+var SupportedLanguages = []string{"nl", "de", "en"}
+
+// IsSupportedLanguage controleert een taalcode (nl, de, en)
+func IsSupportedLanguage(lang string) bool {
+	for _, supported := range SupportedLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveLanguage kiest de taal: ?lang= gaat voor Accept-Language, anders de standaard
+func ResolveLanguage(param, acceptLanguage string) string {
+	if lang := strings.ToLower(strings.TrimSpace(param)); IsSupportedLanguage(lang) {
+		return lang
+	}
+
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		// "de-DE" -> "de"
+		lang := strings.ToLower(strings.SplitN(strings.TrimSpace(fields[0]), "-", 2)[0])
+		if !IsSupportedLanguage(lang) {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{lang, quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
+
+// LanguageFallbacks - volgorde waarin vertalingen gezocht worden:
+// gevraagde taal, dan Nederlands, dan Engels, dan Duits
+func LanguageFallbacks(lang string) []string {
+	chain := []string{lang}
+	for _, fallback := range []string{DefaultLanguage, "en", "de"} {
+		if fallback != lang {
+			chain = append(chain, fallback)
+		}
+	}
+	return chain
+}
+
+// pickTranslation - eerste taal uit de fallback keten die een waarde heeft
+func pickTranslation(values map[string]string, chain []string) (string, string, bool) {
+	for _, lang := range chain {
+		if value, ok := values[lang]; ok && value != "" {
+			return value, lang, true
+		}
+	}
+	return "", "", false
+}
+
+// CategoryNames - vertaalde namen per categorie slug; zonder vertaling blijft de slug
+func CategoryNames(slugs []string, lang string) (map[string]string, error) {
+	names := make(map[string]string, len(slugs))
+	if len(slugs) == 0 {
+		return names, nil
+	}
+
+	chain := LanguageFallbacks(lang)
+
+	var translations []models.CategoryTranslation
+	if err := config.DB.Where("slug IN ? AND language IN ?", slugs, chain).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+
+	bySlug := map[string]map[string]string{}
+	for _, t := range translations {
+		if bySlug[t.Slug] == nil {
+			bySlug[t.Slug] = map[string]string{}
+		}
+		bySlug[t.Slug][t.Language] = t.Name
+	}
+
+	for _, slug := range slugs {
+		names[slug] = slug
+		if name, _, ok := pickTranslation(bySlug[slug], chain); ok {
+			names[slug] = name
+		}
+	}
+
+	return names, nil
+}
+
+// LocalizeBusinesses vult omschrijving en categorienamen in de gevraagde taal.
+// Zonder vertaling blijft de originele omschrijving staan.
+func LocalizeBusinesses(businesses []models.Business, lang string) error {
+	if len(businesses) == 0 {
+		return nil
+	}
+
+	chain := LanguageFallbacks(lang)
+
+	ids := make([]uint, 0, len(businesses))
+	slugSet := map[string]bool{}
+	for _, business := range businesses {
+		ids = append(ids, business.ID)
+		if business.Category != "" {
+			slugSet[business.Category] = true
+		}
+		if business.SubCategory != "" {
+			slugSet[business.SubCategory] = true
+		}
+	}
+
+	var translations []models.BusinessTranslation
+	if err := config.DB.Where("business_id IN ? AND language IN ?", ids, chain).Find(&translations).Error; err != nil {
+		return err
+	}
+
+	descriptions := map[uint]map[string]string{}
+	for _, t := range translations {
+		if descriptions[t.BusinessID] == nil {
+			descriptions[t.BusinessID] = map[string]string{}
+		}
+		descriptions[t.BusinessID][t.Language] = t.Description
+	}
+
+	slugs := make([]string, 0, len(slugSet))
+	for slug := range slugSet {
+		slugs = append(slugs, slug)
+	}
+	names, err := CategoryNames(slugs, lang)
+	if err != nil {
+		return err
+	}
+
+	for i := range businesses {
+		business := &businesses[i]
+		if description, served, ok := pickTranslation(descriptions[business.ID], chain); ok {
+			business.Description = description
+			business.Language = served
+		}
+		business.CategoryName = names[business.Category]
+		business.SubCategoryName = names[business.SubCategory]
+	}
+
+	return nil
+}
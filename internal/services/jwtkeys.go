@@ -0,0 +1,226 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Oude fallback uit eerdere versies; hiermee weigeren we te starten
+const defaultJWTSecret = "default-secret-key-change-in-production"
+
+// signingKey - één sleutel uit de keyring, herkenbaar aan zijn kid
+type signingKey struct {
+	KID     string
+	Method  jwt.SigningMethod
+	Private crypto.Signer // nil voor sleutels die alleen nog geverifieerd worden
+	Public  crypto.PublicKey
+}
+
+// JWK - publieke sleutel in JWKS formaat (RFC 7517)
+type JWK struct {
+	KTY string `json:"kty"`
+	KID string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	CRV string `json:"crv,omitempty"` // OKP (Ed25519)
+	X   string `json:"x,omitempty"`   // OKP (Ed25519)
+	N   string `json:"n,omitempty"`   // RSA
+	E   string `json:"e,omitempty"`   // RSA
+}
+
+var (
+	keyringMu    sync.RWMutex
+	activeKey    *signingKey
+	verifyingKey = map[string]*signingKey{}
+)
+
+// LoadTokenKeys laadt de JWT sleutels uit JWT_KEYS_DIR.
+//
+// Elk bestand <kid>.pem is een private key (Ed25519 of RSA, PKCS#8/PKCS#1) of een
+// public key (PKIX) van een uitgefaseerde sleutel. JWT_ACTIVE_KID bepaalt welke
+// private key tokens tekent; alle sleutels blijven geldig voor verificatie, zodat
+// roteren niemand uitlogt. Nieuwe sleutel: openssl genpkey -algorithm ed25519
+func LoadTokenKeys() error {
+	if secret := os.Getenv("JWT_SECRET"); secret == defaultJWTSecret {
+		return errors.New("JWT_SECRET is set to the insecure default value")
+	}
+
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		return errors.New("JWT_KEYS_DIR is not set")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	keys := map[string]*signingKey{}
+	var privateKIDs []string
+
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", file, err)
+		}
+
+		key, err := parseKeyPEM(kid, data)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", file, err)
+		}
+
+		keys[kid] = key
+		if key.Private != nil {
+			privateKIDs = append(privateKIDs, kid)
+		}
+	}
+
+	activeKID := os.Getenv("JWT_ACTIVE_KID")
+	if activeKID == "" {
+		if len(privateKIDs) != 1 {
+			return fmt.Errorf("JWT_ACTIVE_KID must be set when %d private keys are present", len(privateKIDs))
+		}
+		activeKID = privateKIDs[0]
+	}
+
+	active, ok := keys[activeKID]
+	if !ok || active.Private == nil {
+		return fmt.Errorf("no private key found for active kid %q", activeKID)
+	}
+
+	keyringMu.Lock()
+	activeKey = active
+	verifyingKey = keys
+	keyringMu.Unlock()
+
+	fmt.Printf("🔑 JWT keys loaded: %d verification key(s), signing with %s (%s)\n",
+		len(keys), active.KID, active.Method.Alg())
+
+	return nil
+}
+
+// parseKeyPEM leest een Ed25519 of RSA sleutel uit PEM
+func parseKeyPEM(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	var err error
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case ed25519.PrivateKey:
+		return &signingKey{KID: kid, Method: jwt.SigningMethodEdDSA, Private: key, Public: key.Public()}, nil
+	case ed25519.PublicKey:
+		return &signingKey{KID: kid, Method: jwt.SigningMethodEdDSA, Public: key}, nil
+	case *rsa.PrivateKey:
+		return &signingKey{KID: kid, Method: jwt.SigningMethodRS256, Private: key, Public: key.Public()}, nil
+	case *rsa.PublicKey:
+		return &signingKey{KID: kid, Method: jwt.SigningMethodRS256, Public: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+// signToken tekent claims met de actieve sleutel en zet de kid header
+func signToken(claims jwt.Claims) (string, error) {
+	keyringMu.RLock()
+	key := activeKey
+	keyringMu.RUnlock()
+
+	if key == nil {
+		return "", errors.New("no JWT signing key loaded")
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.KID
+
+	return token.SignedString(key.Private)
+}
+
+// verificationKey zoekt de publieke sleutel bij de kid van een token
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	keyringMu.RLock()
+	key, ok := verifyingKey[kid]
+	keyringMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	// Algoritme moet bij de sleutel passen (geen algorithm confusion)
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.Public, nil
+}
+
+// JWKS geeft alle publieke verificatiesleutels voor /.well-known/jwks.json
+func JWKS() []JWK {
+	keyringMu.RLock()
+	defer keyringMu.RUnlock()
+
+	kids := make([]string, 0, len(verifyingKey))
+	for kid := range verifyingKey {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]JWK, 0, len(kids))
+	for _, kid := range kids {
+		key := verifyingKey[kid]
+		jwk := JWK{KID: kid, Use: "sig", Alg: key.Method.Alg()}
+
+		switch pub := key.Public.(type) {
+		case ed25519.PublicKey:
+			jwk.KTY = "OKP"
+			jwk.CRV = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		case *rsa.PublicKey:
+			jwk.KTY = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		default:
+			continue
+		}
+
+		keys = append(keys, jwk)
+	}
+
+	return keys
+}
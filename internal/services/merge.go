@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMergeSameBusiness   = errors.New("cannot merge a business with itself")
+	ErrAlreadyMerged       = errors.New("business has already been merged")
+	ErrMergeCustomerClash  = errors.New("both businesses are linked to a different customer")
+	ErrUnknownMergeField   = errors.New("unknown merge field")
+	ErrMergeBusinessAbsent = errors.New("business not found")
+)
+
+// mergeField - hoe een veld van het dubbele bedrijf overgenomen wordt. Zonder
+// expliciete keuze wint het overblijvende bedrijf, tenzij dat veld leeg is.
+type mergeField struct {
+	Empty func(b *models.Business) bool
+	Copy  func(to, from *models.Business)
+}
+
+func stringMergeField(get func(b *models.Business) *string) mergeField {
+	return mergeField{
+		Empty: func(b *models.Business) bool { return *get(b) == "" },
+		Copy:  func(to, from *models.Business) { *get(to) = *get(from) },
+	}
+}
+
+// MergeFields - velden die bij samenvoegen gekozen kunnen worden
+var MergeFields = map[string]mergeField{
+	"name":        stringMergeField(func(b *models.Business) *string { return &b.Name }),
+	"address":     stringMergeField(func(b *models.Business) *string { return &b.Address }),
+	"city":        stringMergeField(func(b *models.Business) *string { return &b.City }),
+	"postal_code": stringMergeField(func(b *models.Business) *string { return &b.PostalCode }),
+	"state":       stringMergeField(func(b *models.Business) *string { return &b.State }),
+	"country":     stringMergeField(func(b *models.Business) *string { return &b.Country }),
+	"phone":       stringMergeField(func(b *models.Business) *string { return &b.Phone }),
+	"website":     stringMergeField(func(b *models.Business) *string { return &b.Website }),
+	"email":       stringMergeField(func(b *models.Business) *string { return &b.Email }),
+	"description": stringMergeField(func(b *models.Business) *string { return &b.Description }),
+	"category": { // Categorie en subcategorie horen bij elkaar
+		Empty: func(b *models.Business) bool { return b.Category == "" },
+		Copy: func(to, from *models.Business) {
+			to.Category, to.SubCategory = from.Category, from.SubCategory
+		},
+	},
+	"location": {
+		Empty: func(b *models.Business) bool { return !hasLocation(b) },
+		Copy: func(to, from *models.Business) {
+			to.Latitude, to.Longitude = from.Latitude, from.Longitude
+		},
+	},
+	"customer_id": {
+		Empty: func(b *models.Business) bool { return b.CustomerID == nil },
+		Copy:  func(to, from *models.Business) { to.CustomerID = from.CustomerID },
+	},
+}
+
+// mergeChild verplaatst gekoppelde records van het dubbele naar het overblijvende
+// bedrijf. Geeft foto's terug waarvan de bestanden na de commit weg kunnen.
+type mergeChild func(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error)
+
+// mergeChildren - alles wat aan een bedrijf hangt
+var mergeChildren = []mergeChild{
+	mergePhotos,
+	mergeOpeningHours,
+	mergeHoursExceptions,
+	mergeTranslations,
+	mergeDismissals,
+	mergeFuelPrices,
+	mergeDeals,
+	mergeReviews,
+	mergeProposals,
+}
+
+// MergeBusinesses voegt duplicate samen met survivor. take noemt de velden
+// (zie MergeFields) die van duplicate overgenomen moeten worden. Het dubbele
+// bedrijf blijft als inactief record met merged_into_id bestaan.
+func MergeBusinesses(ctx context.Context, survivorID, duplicateID uint, take []string) (*models.Business, error) {
+	if survivorID == duplicateID {
+		return nil, ErrMergeSameBusiness
+	}
+
+	taken := map[string]bool{}
+	for _, field := range take {
+		if _, ok := MergeFields[field]; !ok {
+			return nil, ErrUnknownMergeField
+		}
+		taken[field] = true
+	}
+
+	var survivor models.Business
+	var removed []models.BusinessPhoto
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var duplicate models.Business
+		if err := tx.First(&survivor, survivorID).Error; err != nil {
+			return ErrMergeBusinessAbsent
+		}
+		if err := tx.First(&duplicate, duplicateID).Error; err != nil {
+			return ErrMergeBusinessAbsent
+		}
+		if survivor.MergedIntoID != nil || duplicate.MergedIntoID != nil {
+			return ErrAlreadyMerged
+		}
+
+		if survivor.CustomerID != nil && duplicate.CustomerID != nil &&
+			*survivor.CustomerID != *duplicate.CustomerID && !taken["customer_id"] {
+			return ErrMergeCustomerClash
+		}
+
+		for name, field := range MergeFields {
+			if taken[name] || (field.Empty(&survivor) && !field.Empty(&duplicate)) {
+				field.Copy(&survivor, &duplicate)
+			}
+		}
+		if err := tx.Omit("Customer").Save(&survivor).Error; err != nil {
+			return err
+		}
+
+		for _, child := range mergeChildren {
+			photos, err := child(tx, &survivor, &duplicate)
+			if err != nil {
+				return err
+			}
+			removed = append(removed, photos...)
+		}
+
+		// Eerder in duplicate samengevoegde bedrijven wijzen nu naar survivor
+		err := tx.Model(&models.Business{}).Where("merged_into_id = ?", duplicate.ID).
+			UpdateColumn("merged_into_id", survivor.ID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&duplicate).Updates(map[string]interface{}{
+			"is_active":      false,
+			"merged_into_id": survivor.ID,
+			"customer_id":    nil,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range removed {
+		DeletePhotoFiles(ctx, &removed[i])
+	}
+
+	return &survivor, nil
+}
+
+// mergePhotos - foto's achter die van survivor; één logo en één hoofdfoto per soort
+func mergePhotos(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	var photos []models.BusinessPhoto
+	if err := tx.Where("business_id = ?", duplicate.ID).Find(&photos).Error; err != nil {
+		return nil, err
+	}
+
+	var maxOrder *int
+	tx.Model(&models.BusinessPhoto{}).Where("business_id = ?", survivor.ID).Select("MAX(sort_order)").Scan(&maxOrder)
+	offset := 0
+	if maxOrder != nil {
+		offset = *maxOrder + 1
+	}
+
+	var removed []models.BusinessPhoto
+	for _, photo := range photos {
+		var primaries int64
+		tx.Model(&models.BusinessPhoto{}).
+			Where("business_id = ? AND kind = ? AND is_primary = ?", survivor.ID, photo.Kind, true).
+			Count(&primaries)
+
+		if photo.Kind == models.PhotoKindLogo && primaries > 0 {
+			if err := tx.Delete(&photo).Error; err != nil {
+				return nil, err
+			}
+			removed = append(removed, photo)
+			continue
+		}
+
+		err := tx.Model(&photo).Updates(map[string]interface{}{
+			"business_id": survivor.ID,
+			"sort_order":  photo.SortOrder + offset,
+			"is_primary":  photo.IsPrimary && primaries == 0,
+		}).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return removed, nil
+}
+
+// mergeOpeningHours - de weekplanning van survivor blijft; heeft die er geen, dan die van duplicate
+func mergeOpeningHours(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	var count int64
+	tx.Model(&models.OpeningHours{}).Where("business_id = ?", survivor.ID).Count(&count)
+
+	if count > 0 {
+		return nil, tx.Where("business_id = ?", duplicate.ID).Delete(&models.OpeningHours{}).Error
+	}
+	return nil, tx.Model(&models.OpeningHours{}).Where("business_id = ?", duplicate.ID).
+		UpdateColumn("business_id", survivor.ID).Error
+}
+
+// mergeHoursExceptions - uitzonderingen op datums die survivor nog niet heeft
+func mergeHoursExceptions(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	existing := tx.Model(&models.OpeningHoursException{}).Select("date").Where("business_id = ?", survivor.ID)
+
+	err := tx.Where("business_id = ? AND date IN (?)", duplicate.ID, existing).Delete(&models.OpeningHoursException{}).Error
+	if err != nil {
+		return nil, err
+	}
+	return nil, tx.Model(&models.OpeningHoursException{}).Where("business_id = ?", duplicate.ID).
+		UpdateColumn("business_id", survivor.ID).Error
+}
+
+// mergeTranslations - vertalingen in talen die survivor nog niet heeft
+func mergeTranslations(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	existing := tx.Model(&models.BusinessTranslation{}).Select("language").Where("business_id = ?", survivor.ID)
+
+	err := tx.Where("business_id = ? AND language IN (?)", duplicate.ID, existing).Delete(&models.BusinessTranslation{}).Error
+	if err != nil {
+		return nil, err
+	}
+	return nil, tx.Model(&models.BusinessTranslation{}).Where("business_id = ?", duplicate.ID).
+		UpdateColumn("business_id", survivor.ID).Error
+}
+
+// mergeDismissals - "geen dubbele" markeringen van het verdwenen bedrijf zijn niet meer nodig
+func mergeDismissals(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	return nil, tx.Where("business_a_id = ? OR business_b_id = ?", duplicate.ID, duplicate.ID).
+		Delete(&models.DuplicateDismissal{}).Error
+}
+
+// mergeFuelPrices - het station ID en de prijshistorie gaan mee als survivor nog geen
+// station heeft; anders blijft alleen de feed van survivor over
+func mergeFuelPrices(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	if duplicate.FuelStationID == nil {
+		return nil, nil
+	}
+
+	// Eerst vrijgeven vanwege de unieke index
+	if err := tx.Model(duplicate).UpdateColumn("fuel_station_id", nil).Error; err != nil {
+		return nil, err
+	}
+
+	if survivor.FuelStationID != nil {
+		return nil, tx.Where("business_id = ?", duplicate.ID).Delete(&models.FuelPrice{}).Error
+	}
+
+	survivor.FuelStationID = duplicate.FuelStationID
+	if err := tx.Model(survivor).UpdateColumn("fuel_station_id", survivor.FuelStationID).Error; err != nil {
+		return nil, err
+	}
+	return nil, tx.Model(&models.FuelPrice{}).Where("business_id = ?", duplicate.ID).
+		UpdateColumn("business_id", survivor.ID).Error
+}
+
+// mergeDeals - deals (en daarmee de inwisselingen) gaan mee naar survivor
+func mergeDeals(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	return nil, tx.Model(&models.Deal{}).Where("business_id = ?", duplicate.ID).
+		UpdateColumn("business_id", survivor.ID).Error
+}
+
+// mergeReviews - reviews gaan mee naar survivor; de score wordt opnieuw berekend
+func mergeReviews(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	err := tx.Model(&models.Review{}).Where("business_id = ?", duplicate.ID).
+		UpdateColumn("business_id", survivor.ID).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := RefreshBusinessRating(tx, duplicate.ID); err != nil {
+		return nil, err
+	}
+	if err := RefreshBusinessRating(tx, survivor.ID); err != nil {
+		return nil, err
+	}
+	return nil, tx.Select("rating_average", "rating_count").First(survivor).Error
+}
+
+// mergeProposals - voorstellen van eigenaren gaan mee; de diff toont eventuele conflicten
+func mergeProposals(tx *gorm.DB, survivor, duplicate *models.Business) ([]models.BusinessPhoto, error) {
+	return nil, tx.Model(&models.BusinessEditProposal{}).Where("business_id = ?", duplicate.ID).
+		UpdateColumn("business_id", survivor.ID).Error
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// OIDCProvider - configuratie van één universiteit (uit OIDC_PROVIDERS_FILE)
+type OIDCProvider struct {
+	ID              string        `json:"id"`   // Gebruikt in de URL: /api/auth/oidc/<id>/login
+	Name            string        `json:"name"` // Weergavenaam op de loginpagina
+	Issuer          string        `json:"issuer"`
+	ClientID        string        `json:"client_id"`
+	ClientSecretEnv string        `json:"client_secret_env"` // Naam van de env variabele met het secret
+	RedirectURL     string        `json:"redirect_url"`
+	Scopes          []string      `json:"scopes"`
+	DefaultRole     models.Role   `json:"default_role"` // Rol voor nieuwe gebruikers
+	University      string        `json:"university"`
+	AutoProvision   bool          `json:"auto_provision"` // Nieuwe studenten aanmaken bij eerste login
+	LinkRoles       []models.Role `json:"link_roles"`     // Bestaande accounts met deze rollen mogen via e-mail gekoppeld worden
+
+	discovery   *oidcDiscovery
+	keys        map[string]interface{}
+	keysFetched time.Time
+	mu          sync.Mutex
+}
+
+// oidcDiscovery - relevante velden uit /.well-known/openid-configuration
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCClaims - de claims uit het ID token die we gebruiken
+type OIDCClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// oidcStateClaims - lopende login (state, PKCE verifier en nonce). Getekend en in een
+// kortlevende HttpOnly cookie bewaard, zodat de callback op elke replica werkt en
+// alleen vanuit dezelfde browser geldig is.
+type oidcStateClaims struct {
+	ProviderID   string `json:"pid"`
+	State        string `json:"state"`
+	CodeVerifier string `json:"cv"`
+	Nonce        string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+const (
+	OIDCStateTTL      = 10 * time.Minute
+	oidcStateAudience = "oidc-login-state" // Geen auth token: ValidateToken vereist een user_id
+)
+
+var (
+	ErrUnknownOIDCProvider = errors.New("unknown oidc provider")
+	ErrInvalidOIDCState    = errors.New("invalid or expired login state")
+	ErrEmailNotVerified    = errors.New("email address is not verified by the identity provider")
+	ErrUserNotProvisioned  = errors.New("no account linked to this login")
+	ErrUserInactive        = errors.New("user account is inactive")
+	ErrOIDCLinkNotAllowed  = errors.New("this account cannot be linked to a university login; sign in with your password")
+
+	oidcProviders = map[string]*OIDCProvider{}
+
+	oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// LoadOIDCProviders leest de providers uit OIDC_PROVIDERS_FILE (optioneel)
+func LoadOIDCProviders() error {
+	path := os.Getenv("OIDC_PROVIDERS_FILE")
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var providers []*OIDCProvider
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	loaded := map[string]*OIDCProvider{}
+	for _, provider := range providers {
+		if provider.ID == "" || provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return fmt.Errorf("oidc provider %q: id, issuer, client_id and redirect_url are required", provider.ID)
+		}
+		if provider.DefaultRole == "" {
+			provider.DefaultRole = models.RoleStudent
+		}
+		if len(provider.LinkRoles) == 0 {
+			provider.LinkRoles = []models.Role{models.RoleStudent}
+		}
+		if len(provider.Scopes) == 0 {
+			provider.Scopes = []string{"openid", "email", "profile"}
+		}
+		provider.Issuer = strings.TrimSuffix(provider.Issuer, "/")
+		loaded[provider.ID] = provider
+	}
+
+	oidcProviders = loaded
+	fmt.Printf("🎓 %d OIDC provider(s) configured\n", len(loaded))
+
+	return nil
+}
+
+// GetOIDCProviders geeft alle geconfigureerde providers
+func GetOIDCProviders() []*OIDCProvider {
+	providers := make([]*OIDCProvider, 0, len(oidcProviders))
+	for _, provider := range oidcProviders {
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// GetOIDCProvider zoekt een provider op id
+func GetOIDCProvider(id string) (*OIDCProvider, error) {
+	provider, ok := oidcProviders[id]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+	return provider, nil
+}
+
+// randomString - URL-veilige willekeurige string
+func randomString(bytes int) (string, error) {
+	buf := make([]byte, bytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// getJSON haalt een JSON document op
+func getJSON(endpoint string, target interface{}) error {
+	resp, err := oidcHTTPClient.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", endpoint, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// discover haalt (eenmalig) de OpenID configuratie van de issuer op
+func (p *OIDCProvider) discover() (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := getJSON(p.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.Issuer {
+		return nil, fmt.Errorf("issuer mismatch: %s", discovery.Issuer)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// AuthCodeURL start een login: geeft de URL waar de gebruiker heen gestuurd wordt en
+// de getekende state (state, nonce en PKCE verifier) voor de login cookie
+func (p *OIDCProvider) AuthCodeURL() (string, string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomString(24)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomString(24)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomString(48)
+	if err != nil {
+		return "", "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	now := time.Now()
+	stateToken, err := signToken(oidcStateClaims{
+		ProviderID:   p.ID,
+		State:        state,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcStateAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(OIDCStateTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.ClientID},
+		"redirect_uri":          {p.RedirectURL},
+		"scope":                 {strings.Join(p.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), stateToken, nil
+}
+
+// parseState controleert de state uit de login cookie tegen de state uit de callback
+func parseState(stateToken, state, providerID string) (*oidcStateClaims, error) {
+	pending := &oidcStateClaims{}
+	_, err := jwt.ParseWithClaims(stateToken, pending, verificationKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithAudience(oidcStateAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || pending.ProviderID != providerID || pending.State == "" ||
+		subtle.ConstantTimeCompare([]byte(pending.State), []byte(state)) != 1 {
+		return nil, ErrInvalidOIDCState
+	}
+	return pending, nil
+}
+
+// Exchange wisselt de authorization code in en valideert het ID token.
+// stateToken is de waarde van de login cookie uit AuthCodeURL.
+func (p *OIDCProvider) Exchange(code, state, stateToken string) (*OIDCClaims, error) {
+	pending, err := parseState(stateToken, state, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.RedirectURL},
+		"client_id":     {p.ClientID},
+		"code_verifier": {pending.CodeVerifier},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if secret := os.Getenv(p.ClientSecretEnv); p.ClientSecretEnv != "" && secret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(secret))
+	}
+
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tokenResponse struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK || tokenResponse.IDToken == "" {
+		return nil, fmt.Errorf("token endpoint error: status %d %s", resp.StatusCode, tokenResponse.Error)
+	}
+
+	claims := &OIDCClaims{}
+	_, err = jwt.ParseWithClaims(tokenResponse.IDToken, claims, p.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(pending.Nonce)) != 1 {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+// keyFunc zoekt de signing key van de provider; onbekende kid → JWKS opnieuw ophalen
+func (p *OIDCProvider) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	// Niet vaker dan eens per minuut opnieuw ophalen
+	if time.Since(p.keysFetched) < time.Minute && p.keys != nil {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			KTY string `json:"kty"`
+			KID string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			CRV string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := getJSON(p.discovery.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]interface{}{}
+	for _, k := range jwks.Keys {
+		switch k.KTY {
+		case "RSA":
+			n, err1 := base64.RawURLEncoding.DecodeString(k.N)
+			e, err2 := base64.RawURLEncoding.DecodeString(k.E)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			keys[k.KID] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch k.CRV {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			default:
+				continue
+			}
+			x, err1 := base64.RawURLEncoding.DecodeString(k.X)
+			y, err2 := base64.RawURLEncoding.DecodeString(k.Y)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			keys[k.KID] = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// CanLink - of een bestaand account met deze rol via het e-mailadres gekoppeld mag
+// worden. Admins en eigenaren standaard niet: anders neemt een IdP account hun login over.
+func (p *OIDCProvider) CanLink(role models.Role) bool {
+	for _, allowed := range p.LinkRoles {
+		if role == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// LoginWithOIDC koppelt de login aan een bestaande gebruiker (via identity of
+// geverifieerd e-mailadres, alleen voor LinkRoles) of maakt een nieuwe student aan
+func LoginWithOIDC(p *OIDCProvider, claims *OIDCClaims) (*models.User, error) {
+	now := time.Now()
+	var user models.User
+
+	// 1. Al eerder gekoppeld
+	var identity models.UserIdentity
+	err := config.DB.Where("issuer = ? AND subject = ?", p.Issuer, claims.Subject).First(&identity).Error
+	if err == nil {
+		if err := config.DB.First(&user, identity.UserID).Error; err != nil {
+			return nil, err
+		}
+		if !user.IsActive {
+			return nil, ErrUserInactive
+		}
+		config.DB.Model(&identity).Update("last_login_at", now)
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Koppelen of aanmaken kan alleen met een geverifieerd e-mailadres
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" || !claims.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// 2. Bestaande gebruiker met hetzelfde e-mailadres
+		err := tx.Where("LOWER(email) = ?", email).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 3. Nieuwe student aanmaken
+			if !p.AutoProvision {
+				return ErrUserNotProvisioned
+			}
+			if _, err := GetRoleDefinition(p.DefaultRole); err != nil {
+				return fmt.Errorf("default role %q of provider %s: %w", p.DefaultRole, p.ID, err)
+			}
+
+			user = models.User{
+				Email:      email,
+				FirstName:  claims.GivenName,
+				LastName:   claims.FamilyName,
+				Role:       p.DefaultRole,
+				University: p.University,
+				IsActive:   true,
+			}
+			if user.FirstName == "" {
+				user.FirstName = strings.Split(email, "@")[0]
+			}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+			fmt.Printf("🎓 Provisioned %s via OIDC provider %s\n", email, p.ID)
+		} else if err != nil {
+			return err
+		} else if !p.CanLink(user.Role) {
+			return ErrOIDCLinkNotAllowed
+		}
+
+		if !user.IsActive {
+			return ErrUserInactive
+		}
+
+		return tx.Create(&models.UserIdentity{
+			UserID:      user.ID,
+			Issuer:      p.Issuer,
+			Subject:     claims.Subject,
+			Email:       email,
+			LastLoginAt: &now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
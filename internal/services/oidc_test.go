@@ -0,0 +1,255 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"projectpeterperplexity/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockIssuer - lokale OIDC provider met discovery, JWKS en een token endpoint dat PKCE controleert
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu        sync.Mutex
+	challenge string // code_challenge uit de authorize URL
+	nonce     string // nonce die in het ID token komt
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "mock",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		m.mu.Lock()
+		challenge, nonce := m.challenge, m.nonce
+		m.mu.Unlock()
+
+		verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "good-code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, OIDCClaims{
+			Email:         "student@example.edu",
+			EmailVerified: true,
+			Nonce:         nonce,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    m.server.URL,
+				Subject:   "student-1",
+				Audience:  jwt.ClaimStrings{"client"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		})
+		token.Header["kid"] = "mock"
+		signed, _ := token.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+// startLogin - AuthCodeURL aanroepen en de challenge en nonce aan de mock doorgeven
+func (m *mockIssuer) startLogin(t *testing.T, provider *OIDCProvider) (state, stateToken string) {
+	t.Helper()
+
+	authURL, stateToken, err := provider.AuthCodeURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil || !strings.HasPrefix(authURL, m.server.URL+"/authorize?") {
+		t.Fatalf("unexpected authorize URL %q", authURL)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("expected PKCE S256, got %q", query.Get("code_challenge_method"))
+	}
+
+	m.mu.Lock()
+	m.challenge = query.Get("code_challenge")
+	m.nonce = query.Get("nonce")
+	m.mu.Unlock()
+
+	return query.Get("state"), stateToken
+}
+
+// useTestSigningKey - tijdelijke sleutel voor de getekende login state
+func useTestSigningKey(t *testing.T) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &signingKey{KID: "test", Method: jwt.SigningMethodEdDSA, Private: private, Public: public}
+
+	keyringMu.Lock()
+	previousActive, previousVerifying := activeKey, verifyingKey
+	activeKey, verifyingKey = key, map[string]*signingKey{"test": key}
+	keyringMu.Unlock()
+
+	t.Cleanup(func() {
+		keyringMu.Lock()
+		activeKey, verifyingKey = previousActive, previousVerifying
+		keyringMu.Unlock()
+	})
+}
+
+func newTestProvider(m *mockIssuer) *OIDCProvider {
+	return &OIDCProvider{
+		ID:          "mock",
+		Issuer:      m.server.URL,
+		ClientID:    "client",
+		RedirectURL: "https://api.example.test/api/auth/oidc/mock/callback",
+		Scopes:      []string{"openid", "email"},
+	}
+}
+
+func TestOIDCExchangeWithPKCE(t *testing.T) {
+	useTestSigningKey(t)
+	m := newMockIssuer(t)
+	provider := newTestProvider(m)
+
+	state, stateToken := m.startLogin(t, provider)
+
+	claims, err := provider.Exchange("good-code", state, stateToken)
+	if err != nil {
+		t.Fatalf("exchange failed: %v", err)
+	}
+	if claims.Email != "student@example.edu" || claims.Subject != "student-1" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+}
+
+func TestOIDCExchangeStateMismatch(t *testing.T) {
+	useTestSigningKey(t)
+	m := newMockIssuer(t)
+	provider := newTestProvider(m)
+
+	_, stateToken := m.startLogin(t, provider)
+	otherState, _ := m.startLogin(t, provider)
+
+	tests := map[string]struct {
+		state, stateToken string
+	}{
+		"state from another login": {otherState, stateToken},
+		"missing cookie":           {otherState, ""},
+		"tampered cookie":          {otherState, stateToken + "x"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := provider.Exchange("good-code", tt.state, tt.stateToken)
+			if !errors.Is(err, ErrInvalidOIDCState) {
+				t.Fatalf("expected ErrInvalidOIDCState, got %v", err)
+			}
+		})
+	}
+
+	// Cookie van een andere provider
+	other := newTestProvider(m)
+	other.ID = "other"
+	state, stateToken := m.startLogin(t, provider)
+	if _, err := other.Exchange("good-code", state, stateToken); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("expected ErrInvalidOIDCState for another provider, got %v", err)
+	}
+}
+
+func TestOIDCExchangeBadNonce(t *testing.T) {
+	useTestSigningKey(t)
+	m := newMockIssuer(t)
+	provider := newTestProvider(m)
+
+	state, stateToken := m.startLogin(t, provider)
+	m.mu.Lock()
+	m.nonce = "replayed-nonce"
+	m.mu.Unlock()
+
+	_, err := provider.Exchange("good-code", state, stateToken)
+	if err == nil || !strings.Contains(err.Error(), "nonce") {
+		t.Fatalf("expected nonce mismatch, got %v", err)
+	}
+}
+
+func TestOIDCExchangeWrongVerifier(t *testing.T) {
+	useTestSigningKey(t)
+	m := newMockIssuer(t)
+	provider := newTestProvider(m)
+
+	state, stateToken := m.startLogin(t, provider)
+	m.mu.Lock()
+	m.challenge = "not-the-challenge"
+	m.mu.Unlock()
+
+	if _, err := provider.Exchange("good-code", state, stateToken); err == nil {
+		t.Fatal("expected the token endpoint to reject the code verifier")
+	}
+}
+
+func TestOIDCStateIsNotAnAuthToken(t *testing.T) {
+	useTestSigningKey(t)
+	m := newMockIssuer(t)
+
+	_, stateToken := m.startLogin(t, newTestProvider(m))
+	if _, err := ValidateToken(stateToken); err == nil {
+		t.Fatal("login state must not be accepted as an auth token")
+	}
+}
+
+func TestOIDCCanLink(t *testing.T) {
+	provider := &OIDCProvider{LinkRoles: []models.Role{models.RoleStudent}}
+
+	if !provider.CanLink(models.RoleStudent) {
+		t.Error("students should be linkable")
+	}
+	for _, role := range []models.Role{models.RoleAdmin, models.RoleOwner} {
+		if provider.CanLink(role) {
+			t.Errorf("%s accounts must not be linked by email", role)
+		}
+	}
+}
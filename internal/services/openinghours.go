@@ -0,0 +1,130 @@
+package services
+
+import (
+	"projectpeterperplexity/internal/holidays"
+	"projectpeterperplexity/internal/models"
+	"regexp"
+	"time"
+	_ "time/tzdata" // Europe/Berlin ook zonder tzdata op de server
+)
+
+// Openingstijden worden altijd in Duitse tijd geëvalueerd
+var BerlinLocation = mustLoadLocation("Europe/Berlin")
+
+var timeOfDayPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$|^24:00$`)
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+// ValidTimeOfDay controleert "HH:MM" (00:00 t/m 24:00)
+func ValidTimeOfDay(value string) bool {
+	return timeOfDayPattern.MatchString(value)
+}
+
+// OpenMoment - een tijdstip uitgesplitst in Duitse datum, weekdag en tijd,
+// plus de dag ervoor (voor openingstijden tot na middernacht)
+type OpenMoment struct {
+	At          time.Time
+	Date        time.Time
+	Weekday     int
+	Time        string // "HH:MM"
+	PrevDate    time.Time
+	PrevWeekday int
+}
+
+// NewOpenMoment zet een tijdstip om naar Europe/Berlin
+func NewOpenMoment(t time.Time) OpenMoment {
+	local := t.In(BerlinLocation)
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	prev := date.AddDate(0, 0, -1)
+
+	return OpenMoment{
+		At:          local,
+		Date:        date,
+		Weekday:     int(date.Weekday()),
+		Time:        local.Format("15:04"),
+		PrevDate:    prev,
+		PrevWeekday: int(prev.Weekday()),
+	}
+}
+
+// effectiveWeekday - op feestdagen gelden de uren van HolidayWeekday
+func effectiveWeekday(date time.Time, state string) int {
+	if holidays.IsHoliday(date, state) {
+		return models.HolidayWeekday
+	}
+	return int(date.Weekday())
+}
+
+// overnight - sluittijd niet na openingstijd: loopt door tot de volgende dag
+func overnight(opens, closes string) bool {
+	return closes <= opens
+}
+
+// openDuring - open op tijdstip t van de dag zelf
+func openDuring(opens, closes, t string) bool {
+	return opens <= t && (closes > t || overnight(opens, closes))
+}
+
+// openFromPreviousDay - nog open na middernacht vanaf de dag ervoor
+func openFromPreviousDay(opens, closes, t string) bool {
+	return overnight(opens, closes) && t < closes
+}
+
+// IsOpenAt bepaalt of een bedrijf open is. Uitzonderingen op een datum gaan voor
+// de weekplanning; op feestdagen zonder feestdaguren is het bedrijf dicht.
+// Dezelfde regels staan als SQL in de open_now filter.
+func IsOpenAt(business *models.Business, hours []models.OpeningHours, exceptions []models.OpeningHoursException, t time.Time) bool {
+	moment := NewOpenMoment(t)
+
+	check := func(date time.Time, weekday int, match func(opens, closes string) bool) bool {
+		var dayExceptions []models.OpeningHoursException
+		for _, exception := range exceptions {
+			if exception.Date.Format("2006-01-02") == date.Format("2006-01-02") {
+				dayExceptions = append(dayExceptions, exception)
+			}
+		}
+
+		if len(dayExceptions) > 0 {
+			for _, exception := range dayExceptions {
+				if !exception.Closed && match(exception.Opens, exception.Closes) {
+					return true
+				}
+			}
+			return false
+		}
+
+		for _, h := range hours {
+			if h.Weekday == weekday && match(h.Opens, h.Closes) {
+				return true
+			}
+		}
+		return false
+	}
+
+	today := effectiveWeekday(moment.Date, business.State)
+	yesterday := effectiveWeekday(moment.PrevDate, business.State)
+
+	return check(moment.Date, today, func(opens, closes string) bool {
+		return openDuring(opens, closes, moment.Time)
+	}) || check(moment.PrevDate, yesterday, func(opens, closes string) bool {
+		return openFromPreviousDay(opens, closes, moment.Time)
+	})
+}
+
+// HolidayStates - voor welke deelstaten (en of landelijk) een datum een feestdag is
+func HolidayStates(date time.Time) (nationwide bool, states []string) {
+	holiday := holidays.On(date)
+	if holiday == nil {
+		return false, nil
+	}
+	if holiday.Nationwide() {
+		return true, nil
+	}
+	return false, holiday.States
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"fmt"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geocoding"
+	"projectpeterperplexity/internal/holidays"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/osm"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// OSMSource - ImportSource van bedrijven uit OpenStreetMap; ExternalID is "node/123"
+const OSMSource = "osm"
+
+// OSMImportStats - resultaat van een OSM import
+type OSMImportStats struct {
+	Created      int
+	Updated      int
+	Unchanged    int
+	Skipped      int // Categorie niet (meer) actief in de categorieboom
+	Merged       int // Bedrijf is samengevoegd met een ander; niet opnieuw aanmaken
+	KeptEdits    int // Velden die een admin gewijzigd heeft en dus niet overschreven zijn
+	HoursSkipped int // opening_hours die we niet konden lezen
+}
+
+// osmField - een bedrijfsveld dat uit OSM gevuld wordt
+type osmField struct {
+	Name string
+	Get  func(b *models.Business) string
+	Set  func(b *models.Business, value string)
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 7, 64)
+}
+
+func parseCoordinate(value string) float64 {
+	parsed, _ := strconv.ParseFloat(value, 64)
+	return parsed
+}
+
+var osmFields = []osmField{
+	{"name", func(b *models.Business) string { return b.Name }, func(b *models.Business, v string) { b.Name = v }},
+	{"category", func(b *models.Business) string { return b.Category }, func(b *models.Business, v string) { b.Category = v }},
+	{"sub_category", func(b *models.Business) string { return b.SubCategory }, func(b *models.Business, v string) { b.SubCategory = v }},
+	{"address", func(b *models.Business) string { return b.Address }, func(b *models.Business, v string) { b.Address = v }},
+	{"city", func(b *models.Business) string { return b.City }, func(b *models.Business, v string) { b.City = v }},
+	{"postal_code", func(b *models.Business) string { return b.PostalCode }, func(b *models.Business, v string) { b.PostalCode = v }},
+	{"country", func(b *models.Business) string { return b.Country }, func(b *models.Business, v string) { b.Country = v }},
+	{"latitude", func(b *models.Business) string { return formatCoordinate(b.Latitude) }, func(b *models.Business, v string) { b.Latitude = parseCoordinate(v) }},
+	{"longitude", func(b *models.Business) string { return formatCoordinate(b.Longitude) }, func(b *models.Business, v string) { b.Longitude = parseCoordinate(v) }},
+	{"phone", func(b *models.Business) string { return b.Phone }, func(b *models.Business, v string) { b.Phone = v }},
+	{"website", func(b *models.Business) string { return b.Website }, func(b *models.Business, v string) { b.Website = v }},
+	{"email", func(b *models.Business) string { return b.Email }, func(b *models.Business, v string) { b.Email = v }},
+}
+
+// poiValues - veldwaarden van een POI, in dezelfde vorm als de getters
+func poiValues(poi *osm.POI) models.StringMap {
+	return models.StringMap{
+		"name":         poi.Name,
+		"category":     poi.Category,
+		"sub_category": poi.SubCategory,
+		"address":      poi.Address,
+		"city":         poi.City,
+		"postal_code":  poi.PostalCode,
+		"country":      poi.Country, // Leeg als het land niet vast te stellen is
+		"latitude":     formatCoordinate(poi.Latitude),
+		"longitude":    formatCoordinate(poi.Longitude),
+		"phone":        poi.Phone,
+		"website":      poi.Website,
+		"email":        poi.Email,
+	}
+}
+
+// hoursSignature - vergelijkbare weergave van openingstijden ("1 08:00-18:00|...")
+func hoursSignature(hours []models.OpeningHours) string {
+	parts := make([]string, len(hours))
+	for i, h := range hours {
+		parts[i] = fmt.Sprintf("%d %s-%s", h.Weekday, h.Opens, h.Closes)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "|")
+}
+
+// osmHours - openingstijden uit de opening_hours tag; ok=false als die onleesbaar is
+func osmHours(poi *osm.POI) ([]models.OpeningHours, bool) {
+	parsed, err := osm.ParseOpeningHours(poi.OpeningHours)
+	if err != nil {
+		return nil, false
+	}
+	hours := make([]models.OpeningHours, len(parsed))
+	for i, h := range parsed {
+		hours[i] = models.OpeningHours{Weekday: h.Weekday, Opens: h.Opens, Closes: h.Closes}
+	}
+	return hours, true
+}
+
+// ImportOSMPOIs maakt bedrijven aan of werkt ze bij op basis van hun OSM ID.
+//
+// Per veld wordt vergeleken met de waarde van de vorige import (ImportSnapshot):
+// is het veld sindsdien niet gewijzigd, dan krijgt het de nieuwe OSM waarde; heeft
+// een admin het aangepast, dan blijft de aanpassing staan. Voor openingstijden
+// geldt hetzelfde op basis van de complete weekplanning.
+func ImportOSMPOIs(pois []*osm.POI, dryRun bool) (OSMImportStats, error) {
+	var stats OSMImportStats
+
+	var existing []models.Business
+	if err := config.DB.Preload("OpeningHours").Where("import_source = ?", OSMSource).Find(&existing).Error; err != nil {
+		return stats, err
+	}
+	byKey := make(map[string]*models.Business, len(existing))
+	for i := range existing {
+		if existing[i].ExternalID != nil {
+			byKey[*existing[i].ExternalID] = &existing[i]
+		}
+	}
+
+	for _, poi := range pois {
+		// Alleen categorieën uit de boom; een onbekende keuken laten we weg
+		if ValidateBusinessCategory(poi.Category, poi.SubCategory) != nil {
+			poi.SubCategory = ""
+			if ValidateBusinessCategory(poi.Category, "") != nil {
+				stats.Skipped++
+				continue
+			}
+		}
+
+		values := poiValues(poi)
+		hours, hoursOK := osmHours(poi)
+		if !hoursOK {
+			stats.HoursSkipped++
+		}
+
+		business, found := byKey[poi.Key]
+		if found && business.MergedIntoID != nil {
+			stats.Merged++
+			continue
+		}
+		if !found {
+			stats.Created++
+			if !dryRun {
+				if err := createOSMBusiness(poi, values, hours); err != nil {
+					return stats, fmt.Errorf("%s: %w", poi.Key, err)
+				}
+			}
+			continue
+		}
+
+		oldCountry, oldPostalCode := business.Country, business.PostalCode
+		changed, kept := mergeOSMFields(business, values)
+		stats.KeptEdits += kept
+
+		// Openingstijden alleen vervangen als niemand ze handmatig gewijzigd heeft
+		replaceHours := false
+		if hoursOK {
+			current := hoursSignature(business.OpeningHours)
+			next := hoursSignature(hours)
+			if current == business.ImportSnapshot["opening_hours"] && current != next {
+				replaceHours = true
+			} else if current != business.ImportSnapshot["opening_hours"] && current != next {
+				stats.KeptEdits++
+			}
+			values["opening_hours"] = next
+		} else {
+			values["opening_hours"] = business.ImportSnapshot["opening_hours"]
+		}
+
+		if !changed && !replaceHours {
+			stats.Unchanged++
+			if !dryRun && !snapshotsEqual(business.ImportSnapshot, values) {
+				config.DB.Model(business).UpdateColumn("import_snapshot", values)
+			}
+			continue
+		}
+
+		stats.Updated++
+		if dryRun {
+			continue
+		}
+
+		business.ImportSnapshot = values
+		if business.State == "" || business.Country != oldCountry || business.PostalCode != oldPostalCode {
+			business.State = osmState(business)
+		}
+
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Omit("Customer", "OpeningHours").Save(business).Error; err != nil {
+				return err
+			}
+			if !replaceHours {
+				return nil
+			}
+			if err := tx.Where("business_id = ?", business.ID).Delete(&models.OpeningHours{}).Error; err != nil {
+				return err
+			}
+			return createHours(tx, business.ID, hours)
+		})
+		if err != nil {
+			return stats, fmt.Errorf("%s: %w", poi.Key, err)
+		}
+	}
+
+	return stats, nil
+}
+
+// mergeOSMFields zet nieuwe OSM waarden op velden die niet handmatig gewijzigd zijn
+func mergeOSMFields(business *models.Business, values models.StringMap) (changed bool, kept int) {
+	for _, field := range osmFields {
+		current := field.Get(business)
+		next := values[field.Name]
+		if current == next {
+			continue
+		}
+
+		last, imported := business.ImportSnapshot[field.Name]
+		if imported && current != last {
+			kept++ // Handmatig gewijzigd sinds de vorige import
+			continue
+		}
+
+		field.Set(business, next)
+		changed = true
+	}
+	return changed, kept
+}
+
+func snapshotsEqual(a, b models.StringMap) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if b[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func createOSMBusiness(poi *osm.POI, values models.StringMap, hours []models.OpeningHours) error {
+	externalID := poi.Key
+	business := models.Business{
+		ImportSource: OSMSource,
+		ExternalID:   &externalID,
+		IsActive:     true,
+	}
+	for _, field := range osmFields {
+		field.Set(&business, values[field.Name])
+	}
+	business.State = osmState(&business)
+
+	values["opening_hours"] = hoursSignature(hours)
+	business.ImportSnapshot = values
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Customer", "OpeningHours").Create(&business).Error; err != nil {
+			return err
+		}
+		return createHours(tx, business.ID, hours)
+	})
+}
+
+// osmState - deelstaat na een import. Een onbekende Duitse reeks laat de huidige staan;
+// buiten Duitsland (of zonder bekend land) is er geen deelstaat.
+func osmState(business *models.Business) string {
+	state := holidays.StateForPostalCode(business.Country, business.PostalCode)
+	if state == "" && geocoding.CountryCode(business.Country) == "DE" && business.Country != "" {
+		return business.State
+	}
+	return state
+}
+
+func createHours(tx *gorm.DB, businessID uint, hours []models.OpeningHours) error {
+	if len(hours) == 0 {
+		return nil
+	}
+	rows := make([]models.OpeningHours, len(hours))
+	for i, h := range hours {
+		rows[i] = models.OpeningHours{BusinessID: businessID, Weekday: h.Weekday, Opens: h.Opens, Closes: h.Closes}
+	}
+	return tx.Create(&rows).Error
+}
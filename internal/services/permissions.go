@@ -0,0 +1,59 @@
+package services
+
+import (
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"sync"
+	"time"
+)
+
+// Rollen worden kort gecached zodat niet elke request de database raakt
+const roleCacheTTL = time.Minute
+
+type cachedRole struct {
+	definition *models.RoleDefinition
+	loadedAt   time.Time
+}
+
+var (
+	roleCache   = map[models.Role]cachedRole{}
+	roleCacheMu sync.RWMutex
+)
+
+// GetRoleDefinition haalt een rol op (uit cache of database)
+func GetRoleDefinition(role models.Role) (*models.RoleDefinition, error) {
+	roleCacheMu.RLock()
+	cached, ok := roleCache[role]
+	roleCacheMu.RUnlock()
+
+	if ok && time.Since(cached.loadedAt) < roleCacheTTL {
+		return cached.definition, nil
+	}
+
+	var definition models.RoleDefinition
+	if err := config.DB.Where("name = ?", role).First(&definition).Error; err != nil {
+		return nil, err
+	}
+
+	roleCacheMu.Lock()
+	roleCache[role] = cachedRole{definition: &definition, loadedAt: time.Now()}
+	roleCacheMu.Unlock()
+
+	return &definition, nil
+}
+
+// HasPermission controleert of een rol een bepaalde permissie heeft
+func HasPermission(role models.Role, permission models.Permission) bool {
+	definition, err := GetRoleDefinition(role)
+	if err != nil {
+		return false
+	}
+	return definition.HasPermission(permission)
+}
+
+// InvalidateRoleCache leegt de cache na het wijzigen van rollen
+func InvalidateRoleCache() {
+	roleCacheMu.Lock()
+	roleCache = map[models.Role]cachedRole{}
+	roleCacheMu.Unlock()
+}
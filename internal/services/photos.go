@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/images"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// FillPhotoURLs zet de publieke URL per variant
+func FillPhotoURLs(photos []models.BusinessPhoto) {
+	for i := range photos {
+		photos[i].URLs = make(map[string]string, len(images.Variants))
+		for _, variant := range images.Variants {
+			photos[i].URLs[variant.Name] = storage.Files.URL(photos[i].Key(variant.Name))
+		}
+	}
+}
+
+// AttachThumbnails vult ThumbnailURL (hoofdfoto) en LogoURL voor een lijst bedrijven
+func AttachThumbnails(businesses []models.Business) error {
+	if len(businesses) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(businesses))
+	for i, business := range businesses {
+		ids[i] = business.ID
+	}
+
+	var photos []models.BusinessPhoto
+	if err := config.DB.Where("business_id IN ? AND is_primary = ?", ids, true).Find(&photos).Error; err != nil {
+		return err
+	}
+
+	byBusiness := map[uint]map[string]string{}
+	for i := range photos {
+		photo := &photos[i]
+		if byBusiness[photo.BusinessID] == nil {
+			byBusiness[photo.BusinessID] = map[string]string{}
+		}
+		byBusiness[photo.BusinessID][photo.Kind] = storage.Files.URL(photo.Key("thumb"))
+	}
+
+	for i := range businesses {
+		urls := byBusiness[businesses[i].ID]
+		businesses[i].ThumbnailURL = urls[models.PhotoKindPhoto]
+		businesses[i].LogoURL = urls[models.PhotoKindLogo]
+	}
+
+	return nil
+}
+
+// SavePhoto verwerkt een upload (varianten, zonder EXIF), zet de bestanden in de
+// opslag en maakt het record aan. De eerste foto wordt hoofdfoto; een nieuw logo
+// vervangt het oude.
+func SavePhoto(ctx context.Context, businessID uint, kind, caption string, data []byte) (*models.BusinessPhoto, error) {
+	processed, err := images.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+
+	photo := models.BusinessPhoto{
+		BusinessID:    businessID,
+		Kind:          kind,
+		StoragePrefix: fmt.Sprintf("businesses/%d/%s", businessID, hex.EncodeToString(random)),
+		Ext:           processed.Outputs[0].Ext,
+		Width:         processed.Width,
+		Height:        processed.Height,
+		Caption:       caption,
+	}
+
+	// Eerst de bestanden, dan pas het record; bij een fout ruimen we op
+	var stored []string
+	for _, output := range processed.Outputs {
+		key := photo.Key(output.Variant)
+		if err := storage.Files.Put(ctx, key, output.Data, output.ContentType); err != nil {
+			deleteKeys(ctx, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+		photo.SizeBytes += int64(len(output.Data))
+	}
+
+	var replaced []models.BusinessPhoto
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if kind == models.PhotoKindLogo {
+			if err := tx.Where("business_id = ? AND kind = ?", businessID, kind).Find(&replaced).Error; err != nil {
+				return err
+			}
+			if len(replaced) > 0 {
+				if err := tx.Delete(&replaced).Error; err != nil {
+					return err
+				}
+			}
+			photo.IsPrimary = true
+		} else {
+			var primaries int64
+			tx.Model(&models.BusinessPhoto{}).Where("business_id = ? AND kind = ? AND is_primary = ?", businessID, kind, true).Count(&primaries)
+			photo.IsPrimary = primaries == 0
+
+			var maxOrder *int
+			tx.Model(&models.BusinessPhoto{}).Where("business_id = ? AND kind = ?", businessID, kind).Select("MAX(sort_order)").Scan(&maxOrder)
+			if maxOrder != nil {
+				photo.SortOrder = *maxOrder + 1
+			}
+		}
+
+		return tx.Create(&photo).Error
+	})
+	if err != nil {
+		deleteKeys(ctx, stored)
+		return nil, err
+	}
+
+	for i := range replaced {
+		DeletePhotoFiles(ctx, &replaced[i])
+	}
+
+	photos := []models.BusinessPhoto{photo}
+	FillPhotoURLs(photos)
+	return &photos[0], nil
+}
+
+// DeletePhotoFiles verwijdert alle varianten uit de opslag (fouten worden gelogd)
+func DeletePhotoFiles(ctx context.Context, photo *models.BusinessPhoto) {
+	keys := make([]string, 0, len(images.Variants))
+	for _, variant := range images.Variants {
+		keys = append(keys, photo.Key(variant.Name))
+	}
+	deleteKeys(ctx, keys)
+}
+
+func deleteKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := storage.Files.Delete(ctx, key); err != nil {
+			fmt.Printf("⚠️  Failed to delete %s: %v\n", key, err)
+		}
+	}
+}
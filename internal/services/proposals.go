@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/holidays"
+	"projectpeterperplexity/internal/models"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrProposalNotFound     = errors.New("proposal not found")
+	ErrProposalNotPending   = errors.New("proposal has already been handled")
+	ErrNoProposalChanges    = errors.New("proposal does not change anything")
+	ErrUnknownProposalField = errors.New("unknown field")
+	ErrInvalidProposalValue = errors.New("invalid value")
+	ErrInvalidOpeningHours  = errors.New("opening hours must look like \"mon 09:00-18:00; tue 09:00-18:00\"")
+)
+
+// ProposalOpeningHours - veldnaam voor de weekplanning in een voorstel
+const ProposalOpeningHours = "opening_hours"
+
+// proposalTextFields - velden die een eigenaar mag wijzigen (categorie, coördinaten
+// en de klantkoppeling blijven bij de admins)
+var proposalTextFields = map[string]func(b *models.Business) *string{
+	"name":        func(b *models.Business) *string { return &b.Name },
+	"address":     func(b *models.Business) *string { return &b.Address },
+	"city":        func(b *models.Business) *string { return &b.City },
+	"postal_code": func(b *models.Business) *string { return &b.PostalCode },
+	"phone":       func(b *models.Business) *string { return &b.Phone },
+	"website":     func(b *models.Business) *string { return &b.Website },
+	"email":       func(b *models.Business) *string { return &b.Email },
+	"description": func(b *models.Business) *string { return &b.Description },
+}
+
+// ProposalFields - alle voorstelbare velden, in de volgorde van de diff
+var ProposalFields = []string{"name", "address", "postal_code", "city", "phone", "email", "website", "description", ProposalOpeningHours}
+
+// weekdayCodes - index is de Weekday waarde van OpeningHours
+var weekdayCodes = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat", "holiday"}
+
+// FormatOpeningHours - weekplanning als tekst, gesorteerd ("mon 09:00-18:00; tue 09:00-18:00").
+// Dezelfde tijden geven altijd dezelfde tekst, zodat voorstellen te vergelijken zijn.
+func FormatOpeningHours(hours []models.OpeningHours) string {
+	sorted := append([]models.OpeningHours(nil), hours...)
+	sort.Slice(sorted, func(i, j int) bool {
+		// Maandag eerst, zondag en feestdag achteraan
+		a, b := (sorted[i].Weekday+6)%7, (sorted[j].Weekday+6)%7
+		if sorted[i].Weekday == models.HolidayWeekday {
+			a = 7
+		}
+		if sorted[j].Weekday == models.HolidayWeekday {
+			b = 7
+		}
+		if a != b {
+			return a < b
+		}
+		return sorted[i].Opens < sorted[j].Opens
+	})
+
+	parts := make([]string, len(sorted))
+	for i, h := range sorted {
+		parts[i] = weekdayCodes[h.Weekday] + " " + h.Opens + "-" + h.Closes
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseOpeningHours - omgekeerde van FormatOpeningHours; leeg betekent geen vaste tijden
+func ParseOpeningHours(value string) ([]models.OpeningHours, error) {
+	hours := []models.OpeningHours{}
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			return nil, ErrInvalidOpeningHours
+		}
+		weekday := -1
+		for i, code := range weekdayCodes {
+			if strings.EqualFold(fields[0], code) {
+				weekday = i
+			}
+		}
+		times := strings.Split(fields[1], "-")
+		if weekday < 0 || len(times) != 2 || !ValidTimeOfDay(times[0]) || !ValidTimeOfDay(times[1]) {
+			return nil, ErrInvalidOpeningHours
+		}
+
+		hours = append(hours, models.OpeningHours{Weekday: weekday, Opens: times[0], Closes: times[1]})
+	}
+	return hours, nil
+}
+
+// normalizeProposalValue - waarde opschonen en controleren
+func normalizeProposalValue(field, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch field {
+	case "name":
+		if value == "" {
+			return "", fmt.Errorf("%w: name cannot be empty", ErrInvalidProposalValue)
+		}
+	case "email":
+		if value != "" {
+			if _, err := mail.ParseAddress(value); err != nil {
+				return "", fmt.Errorf("%w: email", ErrInvalidProposalValue)
+			}
+		}
+	case "website":
+		if value != "" && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			value = "https://" + value
+		}
+	case ProposalOpeningHours:
+		hours, err := ParseOpeningHours(value)
+		if err != nil {
+			return "", err
+		}
+		value = FormatOpeningHours(hours)
+	}
+
+	return value, nil
+}
+
+// proposalValue - huidige waarde van een veld (business.OpeningHours moet geladen zijn)
+func proposalValue(business *models.Business, field string) string {
+	if field == ProposalOpeningHours {
+		return FormatOpeningHours(business.OpeningHours)
+	}
+	return *proposalTextFields[field](business)
+}
+
+// ProposeBusinessEdit slaat een voorstel op met alleen de velden die echt veranderen
+func ProposeBusinessEdit(ctx context.Context, business *models.Business, userID uint, changes map[string]string, message string) (*models.BusinessEditProposal, error) {
+	if err := config.DB.WithContext(ctx).Where("business_id = ?", business.ID).Find(&business.OpeningHours).Error; err != nil {
+		return nil, err
+	}
+
+	proposal := models.BusinessEditProposal{
+		BusinessID:       business.ID,
+		CustomerID:       *business.CustomerID,
+		ProposedByUserID: userID,
+		Changes:          models.StringMap{},
+		Original:         models.StringMap{},
+		Message:          strings.TrimSpace(message),
+		Status:           models.ProposalPending,
+	}
+
+	for field, value := range changes {
+		if _, ok := proposalTextFields[field]; !ok && field != ProposalOpeningHours {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownProposalField, field)
+		}
+		value, err := normalizeProposalValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+
+		current := proposalValue(business, field)
+		if value == current {
+			continue
+		}
+		proposal.Changes[field] = value
+		proposal.Original[field] = current
+	}
+
+	if len(proposal.Changes) == 0 {
+		return nil, ErrNoProposalChanges
+	}
+
+	if err := config.DB.WithContext(ctx).Omit("Business", "ProposedBy").Create(&proposal).Error; err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// ProposalChange - één regel in de diff
+type ProposalChange struct {
+	Field    string `json:"field"`
+	Original string `json:"original"` // Bij het voorstellen
+	Current  string `json:"current"`  // Nu
+	Proposed string `json:"proposed"`
+	Conflict bool   `json:"conflict"` // Sinds het voorstel door iemand anders gewijzigd
+}
+
+// ProposalDiff - per veld oud, huidig en nieuw. proposal.Business (met OpeningHours)
+// moet geladen zijn; zonder bedrijf wordt de oorspronkelijke waarde als huidig getoond.
+func ProposalDiff(proposal *models.BusinessEditProposal) []ProposalChange {
+	diff := []ProposalChange{}
+	for _, field := range ProposalFields {
+		proposed, ok := proposal.Changes[field]
+		if !ok {
+			continue
+		}
+
+		change := ProposalChange{
+			Field:    field,
+			Original: proposal.Original[field],
+			Current:  proposal.Original[field],
+			Proposed: proposed,
+		}
+		if proposal.Business != nil {
+			change.Current = proposalValue(proposal.Business, field)
+		}
+		change.Conflict = change.Current != change.Original
+		diff = append(diff, change)
+	}
+	return diff
+}
+
+// lockPendingProposal - voorstel ophalen en vergrendelen; alleen pending voorstellen.
+// Met customerID alleen voorstellen van die klant (portaal).
+func lockPendingProposal(tx *gorm.DB, proposalID uint, customerID *uint) (*models.BusinessEditProposal, error) {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	if customerID != nil {
+		query = query.Where("customer_id = ?", *customerID)
+	}
+
+	var proposal models.BusinessEditProposal
+	err := query.First(&proposal, proposalID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProposalNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Status != models.ProposalPending {
+		return nil, ErrProposalNotPending
+	}
+	return &proposal, nil
+}
+
+// ApproveProposal voert het voorstel door op het bedrijf. Bij een nieuwe postcode worden
+// de coördinaten en de deelstaat opnieuw bepaald.
+func ApproveProposal(ctx context.Context, proposalID uint, reviewerID *uint, note string) (*models.BusinessEditProposal, error) {
+	var proposal *models.BusinessEditProposal
+
+	err := config.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		proposal, err = lockPendingProposal(tx, proposalID, nil)
+		if err != nil {
+			return err
+		}
+
+		var business models.Business
+		if err := tx.First(&business, proposal.BusinessID).Error; err != nil {
+			return err
+		}
+
+		oldPostalCode := business.PostalCode
+		for field, value := range proposal.Changes {
+			if get, ok := proposalTextFields[field]; ok {
+				*get(&business) = value
+			}
+		}
+
+		oldLat, oldLng := business.Latitude, business.Longitude
+		if business.PostalCode != oldPostalCode {
+			business.Latitude, business.Longitude = 0, 0 // Midden van de nieuwe postcode
+			// Onbekende reeks: de (mogelijk door een admin gezette) deelstaat houden
+			if state := holidays.StateForPostalCode(business.Country, business.PostalCode); state != "" {
+				business.State = state
+			}
+		}
+		if err := GeocodeBusiness(ctx, &business); err != nil {
+			return err
+		}
+		// Geen geocoding of onbekende postcode: oude coördinaten houden in plaats van 0,0
+		if business.Latitude == 0 && business.Longitude == 0 {
+			business.Latitude, business.Longitude = oldLat, oldLng
+		}
+		if err := tx.Omit("Customer").Save(&business).Error; err != nil {
+			return err
+		}
+
+		if value, ok := proposal.Changes[ProposalOpeningHours]; ok {
+			hours, err := ParseOpeningHours(value)
+			if err != nil {
+				return err
+			}
+			if err := tx.Where("business_id = ?", business.ID).Delete(&models.OpeningHours{}).Error; err != nil {
+				return err
+			}
+			for i := range hours {
+				hours[i].BusinessID = business.ID
+			}
+			if len(hours) > 0 {
+				if err := tx.Create(&hours).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return finishProposal(tx, proposal, models.ProposalApproved, reviewerID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proposal, nil
+}
+
+// RejectProposal - voorstel afwijzen met een toelichting voor de eigenaar
+func RejectProposal(ctx context.Context, proposalID uint, reviewerID *uint, note string) (*models.BusinessEditProposal, error) {
+	var proposal *models.BusinessEditProposal
+
+	err := config.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		proposal, err = lockPendingProposal(tx, proposalID, nil)
+		if err != nil {
+			return err
+		}
+		return finishProposal(tx, proposal, models.ProposalRejected, reviewerID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proposal, nil
+}
+
+// WithdrawProposal - eigenaar trekt een eigen voorstel in (alleen binnen de eigen klant)
+func WithdrawProposal(ctx context.Context, proposalID, customerID uint) (*models.BusinessEditProposal, error) {
+	var proposal *models.BusinessEditProposal
+
+	err := config.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		proposal, err = lockPendingProposal(tx, proposalID, &customerID)
+		if err != nil {
+			return err
+		}
+		return finishProposal(tx, proposal, models.ProposalWithdrawn, nil, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proposal, nil
+}
+
+// finishProposal - status zetten met wie en wanneer
+func finishProposal(tx *gorm.DB, proposal *models.BusinessEditProposal, status string, reviewerID *uint, note string) error {
+	now := time.Now()
+	return tx.Model(proposal).Updates(map[string]interface{}{
+		"status":              status,
+		"reviewed_by_user_id": reviewerID,
+		"reviewed_at":         now,
+		"review_note":         strings.TrimSpace(note),
+	}).Error
+}
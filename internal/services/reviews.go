@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+const (
+	spamThreshold        = 1.0 // Vanaf deze score status spam in plaats van pending
+	reviewBurstWindow    = time.Hour
+	reviewBurstLimit     = 3  // Reviews per IP binnen het venster voordat het verdacht wordt
+	minDuplicateTextSize = 20 // Korte teksten ("Top!") zijn vaak gelijk zonder spam te zijn
+)
+
+var ErrReviewNotFound = errors.New("review not found")
+
+// spamWords - woorden die in echte reviews van restaurants en tankstations niet voorkomen
+var spamWords = []string{"casino", "viagra", "bitcoin", "crypto", "forex", "lening", "kredit", "loan", "seo", "porn"}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.|\b[a-z0-9-]+\.(com|net|org|ru|xyz|info|biz|top)\b)`)
+
+// ReviewInput - een nieuwe review zoals de bezoeker hem instuurt
+type ReviewInput struct {
+	BusinessID uint
+	UserID     *uint
+	AuthorName string
+	Rating     int
+	Text       string
+	Language   string
+	VisitDate  *time.Time
+	IP         string
+	Honeypot   string // Verborgen formulierveld; mensen laten het leeg
+}
+
+// normalizeReviewText - kleine letters en enkele spaties, voor de duplicaat-hash
+func normalizeReviewText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// isShouting - vooral hoofdletters
+func isShouting(text string) bool {
+	var letters, upper int
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 20 && upper*10 > letters*6
+}
+
+// hasRepeatedCharacters - zes of meer keer hetzelfde teken ("!!!!!!", "goooooed")
+func hasRepeatedCharacters(text string) bool {
+	var previous rune
+	count := 0
+	for _, r := range text {
+		if r == previous {
+			count++
+			if count >= 6 {
+				return true
+			}
+		} else {
+			previous, count = r, 1
+		}
+	}
+	return false
+}
+
+// containsSpamWord - een van de spamWords als los woord, zodat "Kreditkartenzahlung"
+// of "Museo" niet meetellen
+func containsSpamWord(text string) bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		for _, spam := range spamWords {
+			if word == spam {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scoreReview - spamscore met de redenen; textHash wordt bij de review opgeslagen
+func scoreReview(db *gorm.DB, input ReviewInput, ipHash, textHash string) (float64, []string, error) {
+	var score float64
+	var reasons []string
+	add := func(points float64, reason string) {
+		score += points
+		reasons = append(reasons, reason)
+	}
+
+	if strings.TrimSpace(input.Honeypot) != "" {
+		add(1.0, "honeypot")
+	}
+	if linkPattern.MatchString(input.Text) || linkPattern.MatchString(input.AuthorName) {
+		add(0.6, "contains_link")
+	}
+	if containsSpamWord(input.Text) {
+		add(0.6, "spam_word")
+	}
+	if isShouting(input.Text) {
+		add(0.3, "shouting")
+	}
+	if hasRepeatedCharacters(input.Text) {
+		add(0.3, "repeated_characters")
+	}
+	if (input.Rating == 1 || input.Rating == 5) && len(strings.TrimSpace(input.Text)) < 15 {
+		add(0.2, "short_extreme_rating")
+	}
+
+	// Zelfde tekst al eens ingestuurd (bij welk bedrijf dan ook)
+	if len(normalizeReviewText(input.Text)) >= minDuplicateTextSize {
+		var duplicates int64
+		if err := db.Model(&models.Review{}).Where("text_hash = ?", textHash).Count(&duplicates).Error; err != nil {
+			return 0, nil, err
+		}
+		if duplicates > 0 {
+			add(0.7, "duplicate_text")
+		}
+	}
+
+	if ipHash != "" {
+		var repeat int64
+		err := db.Model(&models.Review{}).
+			Where("ip_hash = ? AND business_id = ? AND status <> ?", ipHash, input.BusinessID, models.ReviewRejected).
+			Count(&repeat).Error
+		if err != nil {
+			return 0, nil, err
+		}
+		if repeat > 0 {
+			add(0.5, "repeat_reviewer")
+		}
+
+		var recent int64
+		err = db.Model(&models.Review{}).
+			Where("ip_hash = ? AND created_at >= ?", ipHash, time.Now().Add(-reviewBurstWindow)).
+			Count(&recent).Error
+		if err != nil {
+			return 0, nil, err
+		}
+		if recent >= reviewBurstLimit {
+			add(0.5, "ip_burst")
+		}
+	}
+
+	// Ingelogde bezoekers zijn minder vaak spammers
+	if input.UserID != nil && score > 0 {
+		score -= 0.3
+	}
+
+	return score, reasons, nil
+}
+
+// SubmitReview slaat een review op in de moderatie wachtrij (pending of spam)
+func SubmitReview(ctx context.Context, input ReviewInput) (*models.Review, error) {
+	db := config.DB.WithContext(ctx)
+
+	ipHash := ""
+	if input.IP != "" {
+		ipHash = HashVisitor(input.IP)
+	}
+	textHash := hashText(normalizeReviewText(input.Text))
+
+	score, reasons, err := scoreReview(db, input, ipHash, textHash)
+	if err != nil {
+		return nil, err
+	}
+
+	review := models.Review{
+		BusinessID:  input.BusinessID,
+		UserID:      input.UserID,
+		AuthorName:  strings.TrimSpace(input.AuthorName),
+		Rating:      input.Rating,
+		Text:        strings.TrimSpace(input.Text),
+		Language:    input.Language,
+		VisitDate:   input.VisitDate,
+		Status:      models.ReviewPending,
+		SpamScore:   score,
+		SpamReasons: reasons,
+		IPHash:      ipHash,
+		TextHash:    textHash,
+	}
+	if score >= spamThreshold {
+		review.Status = models.ReviewSpam
+	}
+
+	if err := db.Omit("Business").Create(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// RefreshBusinessRating - gemiddelde en aantal opnieuw berekenen uit de goedgekeurde reviews
+func RefreshBusinessRating(tx *gorm.DB, businessID uint) error {
+	return tx.Exec(`UPDATE businesses SET
+		rating_count = (SELECT COUNT(*) FROM reviews WHERE business_id = @id AND status = @status),
+		rating_average = (SELECT ROUND(AVG(rating)::numeric, 2) FROM reviews WHERE business_id = @id AND status = @status)
+		WHERE id = @id`,
+		map[string]interface{}{"id": businessID, "status": models.ReviewApproved}).Error
+}
+
+// ModerateReview zet de status (approved, rejected of spam) en werkt de score van het bedrijf bij
+func ModerateReview(ctx context.Context, reviewID uint, status string, moderatorID *uint, note string) (*models.Review, error) {
+	var review models.Review
+
+	err := config.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&review, reviewID).Error; err != nil {
+			return ErrReviewNotFound
+		}
+
+		now := time.Now()
+		err := tx.Model(&review).Updates(map[string]interface{}{
+			"status":               status,
+			"moderated_by_user_id": moderatorID,
+			"moderated_at":         now,
+			"moderation_note":      note,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return RefreshBusinessRating(tx, review.BusinessID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// SetReviewReply - publieke reactie van het bedrijf; een lege reactie verwijdert hem
+func SetReviewReply(ctx context.Context, reviewID uint, reply string, userID *uint) (*models.Review, error) {
+	var review models.Review
+	db := config.DB.WithContext(ctx)
+	if err := db.First(&review, reviewID).Error; err != nil {
+		return nil, ErrReviewNotFound
+	}
+
+	updates := map[string]interface{}{"reply": "", "reply_at": nil, "reply_by_user_id": nil}
+	if reply = strings.TrimSpace(reply); reply != "" {
+		updates = map[string]interface{}{"reply": reply, "reply_at": time.Now(), "reply_by_user_id": userID}
+	}
+
+	if err := db.Model(&review).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// DeleteReview verwijdert een review en werkt de score van het bedrijf bij
+func DeleteReview(ctx context.Context, reviewID uint) error {
+	return config.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var review models.Review
+		if err := tx.First(&review, reviewID).Error; err != nil {
+			return ErrReviewNotFound
+		}
+		if err := tx.Delete(&review).Error; err != nil {
+			return err
+		}
+		return RefreshBusinessRating(tx, review.BusinessID)
+	})
+}
+
+// PublicReviews - zonder spam- en moderatiegegevens
+func PublicReviews(reviews []models.Review) {
+	for i := range reviews {
+		reviews[i].UserID = nil
+		reviews[i].SpamScore = 0
+		reviews[i].SpamReasons = nil
+		reviews[i].ModeratedByUserID = nil
+		reviews[i].ModeratedAt = nil
+		reviews[i].ModerationNote = ""
+		reviews[i].ReplyByUserID = nil
+	}
+}
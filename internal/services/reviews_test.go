@@ -0,0 +1,25 @@
+package services
+
+import "testing"
+
+func TestContainsSpamWord(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Goedkope lening nodig? Bel ons!", true},
+		{"Schneller KREDIT ohne Schufa", true},
+		{"Beste casino-bonus hier", true},
+		{"SEO-diensten voor uw website", true},
+		{"Bezahlung mit Kreditkartenzahlung möglich", false},
+		{"Leuk museo-café naast de tankstelle", false},
+		{"Kreditkarte wird akzeptiert", false},
+		{"Lekkere kroketten en snelle bediening", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := containsSpamWord(tt.text); got != tt.want {
+			t.Errorf("containsSpamWord(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
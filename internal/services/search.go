@@ -0,0 +1,53 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Nederlandse plaatsnamen voor Duitse steden (bezoekers typen "aken", niet "aachen")
+var dutchPlaceNames = map[string]string{
+	"aken":     "aachen",
+	"keulen":   "köln",
+	"kleef":    "kleve",
+	"emmerik":  "emmerich",
+	"gelderen": "geldern",
+	"wezel":    "wesel",
+	"berlijn":  "berlin",
+}
+
+// SearchQuery - genormaliseerde zoekopdracht voor de full-text en trigram zoekfunctie
+type SearchQuery struct {
+	Raw     string   // Zoals ingetypt
+	Terms   []string // Losse woorden (met vertaalde plaatsnamen)
+	TSQuery string   // Voor to_tsquery: woorden met prefix match, OR-gekoppeld
+}
+
+// ParseSearchQuery splitst de zoektekst in woorden. Alleen letters en cijfers
+// blijven over, zodat er geen tsquery operators meegestuurd kunnen worden.
+func ParseSearchQuery(raw string) SearchQuery {
+	query := SearchQuery{Raw: strings.TrimSpace(raw)}
+
+	words := strings.FieldsFunc(strings.ToLower(query.Raw), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var parts []string
+	for _, word := range words {
+		if translated, ok := dutchPlaceNames[word]; ok {
+			word = translated
+		}
+		query.Terms = append(query.Terms, word)
+		parts = append(parts, word+":*")
+	}
+
+	// OR: resultaten met meer overeenkomende woorden krijgen een hogere rank
+	query.TSQuery = strings.Join(parts, " | ")
+
+	return query
+}
+
+// IsEmpty - niets om op te zoeken
+func (q SearchQuery) IsEmpty() bool {
+	return len(q.Terms) == 0
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ListingTierGraceDays - zo lang na de vervaldatum mag een factuur openstaan
+// voordat de vermelding terugvalt naar basic
+const ListingTierGraceDays = 14
+
+// ListingTierSQL - actueel niveau van een bedrijf. Alleen een actieve klant zonder
+// achterstallige facturen houdt zijn niveau; anders (en zonder klant) basic.
+// Wordt per query berekend, zodat een niveau vanzelf vervalt.
+var ListingTierSQL = fmt.Sprintf(`COALESCE((SELECT customers.listing_tier FROM customers
+	WHERE customers.id = businesses.customer_id AND customers.status = '%s'
+	AND NOT EXISTS (SELECT 1 FROM invoices WHERE invoices.customer_id = customers.id
+		AND invoices.status IN ('%s', '%s') AND invoices.due_date < NOW() - INTERVAL '%d days')), '%s')`,
+	models.StatusActive, models.InvoiceSent, models.InvoiceOverdue, ListingTierGraceDays, models.TierBasic)
+
+// ListingTierBoostSQL - standaardsortering (zie models.ListingTierBoost): premium 1, anders 0
+var ListingTierBoostSQL = fmt.Sprintf("(CASE %s WHEN '%s' THEN 1 ELSE 0 END)", ListingTierSQL, models.TierPremium)
+
+// ListingHighlightSQL - featured of premium: uitgelichte marker op de kaart
+var ListingHighlightSQL = fmt.Sprintf("(%s <> '%s')", ListingTierSQL, models.TierBasic)
+
+// WithListingTier selecteert het actuele niveau mee (vult Business.ListingTier)
+func WithListingTier(db *gorm.DB) *gorm.DB {
+	return db.Select("businesses.*, " + ListingTierSQL + " AS listing_tier")
+}
+
+// CustomerListingTier - actueel niveau van een klant, met de reden als het is vervallen
+func CustomerListingTier(ctx context.Context, customer *models.Customer) (string, string, error) {
+	if customer.ListingTier == "" || customer.ListingTier == models.TierBasic {
+		return models.TierBasic, "", nil
+	}
+	if customer.Status != models.StatusActive {
+		return models.TierBasic, "customer is not active", nil
+	}
+
+	var overdue int64
+	err := config.DB.WithContext(ctx).Model(&models.Invoice{}).
+		Where("customer_id = ? AND status IN ? AND due_date < ?", customer.ID,
+			[]models.InvoiceStatus{models.InvoiceSent, models.InvoiceOverdue},
+			time.Now().AddDate(0, 0, -ListingTierGraceDays)).
+		Count(&overdue).Error
+	if err != nil {
+		return "", "", err
+	}
+	if overdue > 0 {
+		return models.TierBasic, fmt.Sprintf("%d unpaid invoice(s) more than %d days overdue", overdue, ListingTierGraceDays), nil
+	}
+
+	return customer.ListingTier, "", nil
+}
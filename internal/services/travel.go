@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"projectpeterperplexity/internal/border"
+	"projectpeterperplexity/internal/geo"
+	"projectpeterperplexity/internal/geocoding"
+	"projectpeterperplexity/internal/models"
+	"regexp"
+	"strings"
+)
+
+const (
+	roadDetourFactor = 1.3 // Wegafstand t.o.v. hemelsbreed (gemiddelde voor de grensregio)
+	averageSpeedKmh  = 70  // Mix van snelweg en binnenwegen
+)
+
+var (
+	ErrInvalidOrigin   = errors.New("must be a Dutch postal code (7511 AB) or lat,lng")
+	ErrUnknownPostcode = errors.New("postal code not found")
+	ErrNoPostcodeData  = errors.New("postal code lookup is not available: the server has no Dutch postcode data (GEOCODING_POSTCODES with NL.zip); use lat,lng")
+)
+
+// dutchPostcode - PC4 met optioneel de twee letters
+var dutchPostcode = regexp.MustCompile(`^[1-9][0-9]{3} ?([A-Za-z]{2})?$`)
+
+// TravelOrigin - vertrekpunt voor afstanden
+type TravelOrigin struct {
+	Point      geo.Point `json:"point"`
+	PostalCode string    `json:"postal_code,omitempty"`
+	City       string    `json:"city,omitempty"`
+}
+
+// ResolveOrigin leest ?from=: een Nederlandse postcode of "lat,lng"
+func ResolveOrigin(ctx context.Context, from string) (TravelOrigin, error) {
+	from = strings.TrimSpace(from)
+
+	if strings.Contains(from, ",") {
+		point, err := geo.ParsePoint(from)
+		if err != nil {
+			return TravelOrigin{}, ErrInvalidOrigin
+		}
+		return TravelOrigin{Point: point}, nil
+	}
+
+	if !dutchPostcode.MatchString(from) {
+		return TravelOrigin{}, ErrInvalidOrigin
+	}
+	if geocoding.Default == nil {
+		return TravelOrigin{}, ErrNoPostcodeData
+	}
+
+	result, err := geocoding.Default.Geocode(ctx, geocoding.Address{PostalCode: from, Country: "NL"})
+	if errors.Is(err, geocoding.ErrNotFound) {
+		return TravelOrigin{}, ErrUnknownPostcode
+	}
+	if err != nil {
+		return TravelOrigin{}, err
+	}
+
+	return TravelOrigin{Point: result.Point, PostalCode: geocoding.NormalizePostalCode(from), City: result.City}, nil
+}
+
+// EstimateTravel - hemelsbrede en geschatte wegafstand plus reistijd, en de
+// dichtstbijzijnde grensovergang vanaf de bestemming
+func EstimateTravel(from, to geo.Point) models.TravelInfo {
+	straight := geo.DistanceKm(from, to)
+	road := straight * roadDetourFactor
+	crossing, borderKm := border.Nearest(to)
+
+	return models.TravelInfo{
+		StraightKm:      round1(straight),
+		RoadKm:          round1(road),
+		DurationMinutes: int(math.Ceil(road / averageSpeedKmh * 60)),
+		NearestBorder: models.BorderDistance{
+			Name:       crossing.Name,
+			Road:       crossing.Road,
+			DistanceKm: round1(borderKm),
+		},
+	}
+}
+
+// AttachTravel vult Travel voor een lijst bedrijven
+func AttachTravel(origin geo.Point, businesses []models.Business) {
+	for i := range businesses {
+		travel := EstimateTravel(origin, geo.Point{Lat: businesses[i].Latitude, Lng: businesses[i].Longitude})
+		businesses[i].Travel = &travel
+	}
+}
+
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
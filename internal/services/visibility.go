@@ -0,0 +1,79 @@
+package services
+
+import (
+	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/models"
+)
+
+// Visibility beschrijft welke gebruikers (studenten) iemand mag inzien
+type Visibility struct {
+	All     bool   // Alles zichtbaar (admin)
+	UserIDs []uint // Anders: alleen data van deze gebruikers
+}
+
+// Allows controleert of data van een gebruiker zichtbaar is
+func (v Visibility) Allows(userID uint) bool {
+	if v.All {
+		return true
+	}
+	for _, id := range v.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// SupervisedTeamIDs geeft de teams die een gebruiker begeleidt
+func SupervisedTeamIDs(userID uint) ([]uint, error) {
+	var teamIDs []uint
+	err := config.DB.Model(&models.Team{}).
+		Where("supervisor_id = ?", userID).
+		Pluck("id", &teamIDs).Error
+	return teamIDs, err
+}
+
+// TeamMemberIDs geeft alle leden van de opgegeven teams
+func TeamMemberIDs(teamIDs []uint) ([]uint, error) {
+	var memberIDs []uint
+	if len(teamIDs) == 0 {
+		return memberIDs, nil
+	}
+	err := config.DB.Table("team_members").
+		Where("team_id IN ?", teamIDs).
+		Distinct().
+		Pluck("user_id", &memberIDs).Error
+	return memberIDs, err
+}
+
+// GetVisibility bepaalt welke data een gebruiker mag zien:
+// eigen data, plus die van teamleden als hij supervisor is met team:read
+func GetVisibility(userID uint, role models.Role) (Visibility, error) {
+	if HasPermission(role, models.PermAll) {
+		return Visibility{All: true}, nil
+	}
+
+	visibility := Visibility{UserIDs: []uint{userID}}
+
+	if !HasPermission(role, models.PermTeamRead) {
+		return visibility, nil
+	}
+
+	teamIDs, err := SupervisedTeamIDs(userID)
+	if err != nil {
+		return visibility, err
+	}
+
+	memberIDs, err := TeamMemberIDs(teamIDs)
+	if err != nil {
+		return visibility, err
+	}
+
+	for _, id := range memberIDs {
+		if id != userID {
+			visibility.UserIDs = append(visibility.UserIDs, id)
+		}
+	}
+
+	return visibility, nil
+}
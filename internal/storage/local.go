@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local - bestanden op schijf, geserveerd door de API zelf onder BaseURL
+type Local struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewLocal maakt de map aan als die nog niet bestaat
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Local{Dir: dir, BaseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.Dir, filepath.FromSlash(key))
+}
+
+// Put schrijft eerst naar een tijdelijk bestand, zodat er nooit half geschreven bestanden staan
+func (l *Local) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	path := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	err := os.Remove(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) URL(key string) string {
+	return l.BaseURL + "/" + key
+}
@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3 - S3-compatibele opslag (AWS, MinIO, Hetzner, ...) met AWS Signature V4
+type S3 struct {
+	Endpoint  string // https://s3.eu-central-1.amazonaws.com of http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string // Basis URL voor publieke links, standaard endpoint/bucket
+	PathStyle bool   // endpoint/bucket/key in plaats van bucket.endpoint/key (MinIO)
+
+	client *http.Client
+}
+
+// NewS3FromEnv leest S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY,
+// S3_PUBLIC_URL en S3_PATH_STYLE
+func NewS3FromEnv() (*S3, error) {
+	s3 := &S3{
+		Endpoint:  strings.TrimSuffix(os.Getenv("S3_ENDPOINT"), "/"),
+		Region:    getEnv("S3_REGION", "us-east-1"),
+		Bucket:    os.Getenv("S3_BUCKET"),
+		AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("S3_SECRET_KEY"),
+		PublicURL: strings.TrimSuffix(os.Getenv("S3_PUBLIC_URL"), "/"),
+		PathStyle: os.Getenv("S3_PATH_STYLE") == "true",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if s3.Endpoint == "" || s3.Bucket == "" || s3.AccessKey == "" || s3.SecretKey == "" {
+		return nil, errors.New("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required")
+	}
+	if _, err := url.Parse(s3.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT: %w", err)
+	}
+
+	if s3.PublicURL == "" {
+		s3.PublicURL = s3.bucketURL()
+	}
+
+	return s3, nil
+}
+
+// bucketURL - basis URL van de bucket (path-style of virtual-hosted)
+func (s *S3) bucketURL() string {
+	if s.PathStyle {
+		return s.Endpoint + "/" + s.Bucket
+	}
+	endpoint, _ := url.Parse(s.Endpoint)
+	return endpoint.Scheme + "://" + s.Bucket + "." + endpoint.Host
+}
+
+func (s *S3) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	headers := map[string]string{
+		"Content-Type":  contentType,
+		"Cache-Control": "public, max-age=31536000, immutable", // Keys zijn uniek per upload
+	}
+	return s.do(ctx, http.MethodPut, key, data, headers)
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	// S3 geeft 204 ook als het object niet bestond
+	return s.do(ctx, http.MethodDelete, key, nil, nil)
+}
+
+func (s *S3) URL(key string) string {
+	return s.PublicURL + "/" + escapeKey(key)
+}
+
+// do voert een getekend request uit
+func (s *S3) do(ctx context.Context, method, key string, body []byte, headers map[string]string) error {
+	target, err := url.Parse(s.bucketURL() + "/" + escapeKey(key))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	return nil
+}
+
+// sign zet de Authorization header volgens AWS Signature Version 4
+func (s *S3) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// Alle headers die we zelf zetten ondertekenen, plus host
+	names := []string{"host"}
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	var signed []string
+	canonicalHeaders := ""
+	for _, name := range names {
+		value := req.URL.Host
+		if name != "host" {
+			value = strings.TrimSpace(req.Header.Get(name))
+		}
+		signed = append(signed, name)
+		canonicalHeaders += name + ":" + value + "\n"
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+}
+
+// escapeKey - URI encoding per path segment (S3 laat "/" staan)
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Storage - opslag voor geüploade bestanden (foto's, logo's)
+type Storage interface {
+	// Put slaat data op onder key ("businesses/12/abc/thumb.jpg")
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete verwijdert een bestand; een ontbrekend bestand is geen fout
+	Delete(ctx context.Context, key string) error
+	// URL geeft de publieke URL van een bestand
+	URL(key string) string
+}
+
+// Files - de geconfigureerde opslag (gezet door Load)
+var Files Storage
+
+// Load kiest de opslag op basis van STORAGE_DRIVER (local of s3)
+func Load() error {
+	driver := strings.ToLower(os.Getenv("STORAGE_DRIVER"))
+
+	switch driver {
+	case "", "local":
+		local, err := NewLocal(getEnv("STORAGE_LOCAL_DIR", "./uploads"), getEnv("STORAGE_PUBLIC_URL", "/uploads"))
+		if err != nil {
+			return err
+		}
+		Files = local
+	case "s3":
+		s3, err := NewS3FromEnv()
+		if err != nil {
+			return err
+		}
+		Files = s3
+	default:
+		return fmt.Errorf("unknown STORAGE_DRIVER %q", driver)
+	}
+
+	fmt.Printf("🗄️  File storage: %s\n", driverName(driver))
+	return nil
+}
+
+// validKey - geen lege keys en geen path traversal
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "..") || strings.Contains(key, "\\") {
+		return errors.New("invalid storage key")
+	}
+	return nil
+}
+
+func driverName(driver string) string {
+	if driver == "" {
+		return "local"
+	}
+	return driver
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -2,10 +2,16 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"projectpeterperplexity/internal/config"
+	"projectpeterperplexity/internal/geocoding"
 	"projectpeterperplexity/internal/handlers"
 	"projectpeterperplexity/internal/middleware"
+	"projectpeterperplexity/internal/models"
+	"projectpeterperplexity/internal/services"
+	"projectpeterperplexity/internal/storage"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -27,6 +33,39 @@ func setupLoginRateLimiter() gin.HandlerFunc {
 	return ginlimiter.NewMiddleware(instance)
 }
 
+func setupOIDCRateLimiter() gin.HandlerFunc {
+	// 20 SSO redirects/callbacks per 15 minutes (los van de wachtwoord login)
+	rate := limiter.Rate{
+		Period: 15 * time.Minute,
+		Limit:  20,
+	}
+	store := memory.NewStore()
+	instance := limiter.New(store, rate)
+	return ginlimiter.NewMiddleware(instance)
+}
+
+func setupRedeemRateLimiter() gin.HandlerFunc {
+	// 20 deals inwisselen per uur
+	rate := limiter.Rate{
+		Period: 1 * time.Hour,
+		Limit:  20,
+	}
+	store := memory.NewStore()
+	instance := limiter.New(store, rate)
+	return ginlimiter.NewMiddleware(instance)
+}
+
+func setupReviewRateLimiter() gin.HandlerFunc {
+	// 5 reviews per hour
+	rate := limiter.Rate{
+		Period: 1 * time.Hour,
+		Limit:  5,
+	}
+	store := memory.NewStore()
+	instance := limiter.New(store, rate)
+	return ginlimiter.NewMiddleware(instance)
+}
+
 func setupGlobalRateLimiter() gin.HandlerFunc {
 	// 100 requests per minute globally
 	rate := limiter.Rate{
@@ -38,14 +77,52 @@ func setupGlobalRateLimiter() gin.HandlerFunc {
 	return ginlimiter.NewMiddleware(instance)
 }
 
+// trustedProxies - proxies uit TRUSTED_PROXIES (leeg = geen)
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 func main() {
+	// JWT keys (refuse to start without proper keys)
+	if err := services.LoadTokenKeys(); err != nil {
+		log.Fatal("Failed to load JWT keys: ", err)
+	}
+
+	// Opslag voor foto's en logo's (lokaal of S3)
+	if err := storage.Load(); err != nil {
+		log.Fatal("Failed to configure file storage: ", err)
+	}
+
+	// Postcode dataset voor geocoding (optioneel)
+	if err := geocoding.Load(); err != nil {
+		log.Fatal("Failed to load geocoding data: ", err)
+	}
+
 	// Database setup
 	config.ConnectDatabase()
 	config.MigrateDatabase()
 
+	// University SSO providers (optional)
+	if err := services.LoadOIDCProviders(); err != nil {
+		log.Fatal("Failed to load OIDC providers: ", err)
+	}
+
 	// Gin router
 	r := gin.Default()
 
+	// Alleen X-Forwarded-For van eigen proxies vertrouwen (TRUSTED_PROXIES, komma-gescheiden
+	// IP's of CIDR's). Zonder die variabele telt het IP van de verbinding: anders kiest een
+	// client zelf het IP waarop de rate limiters tellen.
+	if err := r.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES: ", err)
+	}
+
 	// Global rate limiter (applies to all routes)
 	r.Use(setupGlobalRateLimiter())
 
@@ -55,8 +132,8 @@ func main() {
 			"https://www.burogrenstoerisme.nl", // Custom domain
 			"https://burogrenstoerisme.nl",     // Root domain
 		},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -64,15 +141,66 @@ func main() {
 	// Login rate limiter (stricter)
 	loginLimiter := setupLoginRateLimiter()
 
+	// Universiteitslogin (eigen limiet, telt niet mee voor /login)
+	oidcLimiter := setupOIDCRateLimiter()
+
+	// Reviews plaatsen (tegen spam)
+	reviewLimiter := setupReviewRateLimiter()
+
+	// Deals inwisselen (visitor_id komt van de client)
+	redeemLimiter := setupRedeemRateLimiter()
+
+	// Public keys for token verification
+	r.GET("/.well-known/jwks.json", handlers.GetJWKS)
+
+	// Lokaal opgeslagen uploads zelf serveren (bij S3 komen ze uit de bucket)
+	if local, ok := storage.Files.(*storage.Local); ok && strings.HasPrefix(local.BaseURL, "/") {
+		r.Static(local.BaseURL, local.Dir)
+	}
+
 	// API routes
 	api := r.Group("/api")
 	{
 		// Public routes with strict rate limiting
 		api.POST("/login", loginLimiter, handlers.Login)
 
+		// University SSO (OIDC)
+		api.GET("/auth/oidc/providers", handlers.GetOIDCProviders)
+		api.GET("/auth/oidc/:provider/login", oidcLimiter, handlers.OIDCLogin)
+		api.GET("/auth/oidc/:provider/callback", oidcLimiter, handlers.OIDCCallback)
+
 		// Public business routes (voor frontend)
 		api.GET("/businesses", handlers.GetBusinesses)
+		api.GET("/businesses/facets", handlers.GetBusinessFacets)
 		api.GET("/businesses/:id", handlers.GetBusinessByID)
+		api.GET("/businesses/:id/travel", handlers.GetBusinessTravel) // ?from=7511AB of lat,lng
+		api.GET("/businesses/:id/fuel-prices", handlers.GetBusinessFuelPrices)
+		api.GET("/businesses/:id/deals", handlers.GetBusinessDeals)
+
+		// Reviews: lezen (goedgekeurd) en plaatsen (anoniem of ingelogd, eerst moderatie)
+		api.GET("/businesses/:id/reviews", handlers.GetBusinessReviews)
+		api.POST("/businesses/:id/reviews", reviewLimiter, middleware.OptionalAuthMiddleware(), handlers.CreateReview)
+
+		// Deals van klanten
+		api.GET("/deals", handlers.GetDeals)
+		api.POST("/deals/:id/redeem", redeemLimiter, middleware.OptionalAuthMiddleware(), handlers.RedeemDeal)
+
+		// Brandstofprijzen: goedkoopste in de buurt en Nederlandse referentieprijs
+		api.GET("/fuel-prices/cheapest", handlers.GetCheapestFuel)
+		api.GET("/fuel-prices/reference", handlers.GetFuelReferencePrices)
+
+		// Grensovergangen NL-DE (voor afstand tot de grens)
+		api.GET("/border-crossings", handlers.GetBorderCrossings)
+
+		// Categorieboom (?lang=)
+		api.GET("/categories", handlers.GetCategories)
+
+		// Duitse feestdagen (NRW, Niedersachsen)
+		api.GET("/holidays", handlers.GetHolidays)
+
+		// Kaart (GeoJSON)
+		api.GET("/map/businesses", handlers.GetBusinessesGeoJSON)
+		api.GET("/map/clusters", handlers.GetBusinessClusters)
 
 		// Protected routes (auth required)
 		protected := api.Group("/")
@@ -81,20 +209,176 @@ func main() {
 			// Profile
 			protected.GET("/profile", handlers.GetProfile)
 
-			// Admin only routes
+			// Admin routes (per route een permissie)
 			admin := protected.Group("/admin")
-			admin.Use(middleware.AdminOnly())
 			{
-				admin.POST("/register", loginLimiter, handlers.Register) // Also rate limit registration
-				admin.POST("/businesses", handlers.CreateBusiness)
-				// Hier komen later customer/invoice endpoints
+				admin.POST("/register", loginLimiter, middleware.RequirePermission(models.PermUsersManage), handlers.Register) // Also rate limit registration
+
+				// Bedrijven beheren
+				businesses := admin.Group("/businesses")
+				businesses.Use(middleware.RequirePermission(models.PermBusinessesWrite))
+				{
+					businesses.GET("", handlers.GetAdminBusinesses)
+					businesses.POST("", handlers.CreateBusiness)
+					businesses.POST("/import", handlers.ImportBusinesses)        // CSV/XLSX, standaard dry-run
+					businesses.POST("/geocode", handlers.CheckBusinessLocations) // Coördinaten controleren tegen de postcode
+					businesses.GET("/duplicates", handlers.GetDuplicateBusinesses)
+					businesses.POST("/duplicates/dismiss", handlers.DismissDuplicate)
+					businesses.POST("/:id/merge", middleware.RequirePermission(models.PermBusinessesDelete), handlers.MergeBusinesses)
+					businesses.PUT("/:id", handlers.UpdateBusiness)
+					businesses.PATCH("/:id", handlers.UpdateBusiness)
+					businesses.POST("/:id/deactivate", handlers.DeactivateBusiness)
+					businesses.POST("/:id/activate", handlers.ActivateBusiness)
+					businesses.DELETE("/:id", middleware.RequirePermission(models.PermBusinessesDelete), handlers.DeleteBusiness)
+
+					// Openingstijden
+					businesses.PUT("/:id/opening-hours", handlers.SetOpeningHours)
+					businesses.POST("/:id/hours-exceptions", handlers.CreateHoursException)
+					businesses.DELETE("/:id/hours-exceptions/:exceptionId", handlers.DeleteHoursException)
+
+					// Foto's en logo
+					businesses.GET("/:id/photos", handlers.GetBusinessPhotos)
+					businesses.POST("/:id/photos", handlers.UploadBusinessPhoto)
+					businesses.PUT("/:id/photos/order", handlers.ReorderBusinessPhotos)
+					businesses.POST("/:id/photos/:photoId/primary", handlers.SetPrimaryPhoto)
+					businesses.DELETE("/:id/photos/:photoId", handlers.DeleteBusinessPhoto)
+
+					// Vertalingen (nl, de, en)
+					businesses.GET("/:id/translations", handlers.GetBusinessTranslations)
+					businesses.PUT("/:id/translations/:lang", handlers.SetBusinessTranslation)
+					businesses.DELETE("/:id/translations/:lang", handlers.DeleteBusinessTranslation)
+
+					// Deals (alleen bij een actieve klant)
+					businesses.GET("/:id/deals", handlers.GetAdminBusinessDeals)
+					businesses.POST("/:id/deals", handlers.CreateDeal)
+					businesses.GET("/:id/deals/report", handlers.GetDealReport)
+				}
+
+				// Deals wijzigen en verwijderen
+				deals := admin.Group("/deals")
+				deals.Use(middleware.RequirePermission(models.PermBusinessesWrite))
+				{
+					deals.PUT("/:id", handlers.UpdateDeal)
+					deals.DELETE("/:id", handlers.DeleteDeal)
+				}
+
+				// Categorieboom
+				categories := admin.Group("/categories")
+				categories.Use(middleware.RequirePermission(models.PermBusinessesWrite))
+				{
+					categories.GET("", handlers.GetAdminCategories)
+					categories.POST("", handlers.CreateCategory)
+					categories.PUT("/:id", handlers.UpdateCategory)
+					categories.DELETE("/:id", handlers.DeleteCategory)
+				}
+
+				// Categorienamen per taal
+				categoryTranslations := admin.Group("/category-translations")
+				categoryTranslations.Use(middleware.RequirePermission(models.PermBusinessesWrite))
+				{
+					categoryTranslations.GET("", handlers.GetCategoryTranslations)
+					categoryTranslations.PUT("/:slug/:lang", handlers.SetCategoryTranslation)
+					categoryTranslations.DELETE("/:slug/:lang", handlers.DeleteCategoryTranslation)
+				}
+
+				// Wijzigingsvoorstellen van eigenaren (met diff)
+				proposals := admin.Group("/proposals")
+				proposals.Use(middleware.RequirePermission(models.PermBusinessesWrite))
+				{
+					proposals.GET("", handlers.GetProposals)
+					proposals.GET("/:id", handlers.GetProposal)
+					proposals.POST("/:id/approve", handlers.ApproveProposal)
+					proposals.POST("/:id/reject", handlers.RejectProposal)
+				}
+
+				// Moderatie van reviews en reacties van het bedrijf
+				reviews := admin.Group("/reviews")
+				reviews.Use(middleware.RequirePermission(models.PermReviewsModerate))
+				{
+					reviews.GET("", handlers.GetReviewQueue)
+					reviews.POST("/:id/approve", handlers.ApproveReview)
+					reviews.POST("/:id/reject", handlers.RejectReview)
+					reviews.POST("/:id/spam", handlers.MarkReviewSpam)
+					reviews.PUT("/:id/reply", handlers.SetReviewReply)
+					reviews.DELETE("/:id", handlers.DeleteReview)
+				}
+
+				// Brandstofprijzen aanleveren (admin of API key met scope fuelprices:write)
+				fuelPrices := admin.Group("/fuel-prices")
+				fuelPrices.Use(middleware.RequirePermission(models.PermFuelPricesWrite))
+				{
+					fuelPrices.POST("", handlers.IngestFuelPrices)
+					fuelPrices.PUT("/reference/:fuel", handlers.SetFuelReferencePrice)
+				}
+
+				// Rollen en permissies
+				roles := admin.Group("/")
+				roles.Use(middleware.RequirePermission(models.PermRolesManage))
+				{
+					roles.GET("/permissions", handlers.GetPermissions)
+					roles.GET("/roles", handlers.GetRoles)
+					roles.POST("/roles", handlers.CreateRole)
+					roles.PUT("/roles/:id", handlers.UpdateRole)
+					roles.DELETE("/roles/:id", handlers.DeleteRole)
+				}
+
+				// Teams
+				teams := admin.Group("/teams")
+				teams.Use(middleware.RequirePermission(models.PermUsersManage))
+				{
+					teams.GET("", handlers.GetTeams)
+					teams.POST("", handlers.CreateTeam)
+					teams.PUT("/:id", handlers.UpdateTeam)
+					teams.DELETE("/:id", handlers.DeleteTeam)
+					teams.POST("/:id/members", handlers.AddTeamMembers)
+					teams.DELETE("/:id/members/:userId", handlers.RemoveTeamMember)
+				}
+
+				// API keys voor machine clients
+				apiKeys := admin.Group("/api-keys")
+				apiKeys.Use(middleware.RequirePermission(models.PermAPIKeysManage))
+				{
+					apiKeys.GET("", handlers.GetAPIKeys)
+					apiKeys.POST("", handlers.CreateAPIKey)
+					apiKeys.DELETE("/:id", handlers.RevokeAPIKey)
+				}
+				// Vermeldingsniveau hoort bij het betaalde abonnement: alleen financiën en admins
+				admin.PUT("/customers/:id/listing-tier", middleware.RequirePermission(models.PermInvoicesWrite), handlers.SetCustomerListingTier)
+
+				// Hier komen later invoice endpoints
+			}
+
+			// Portaal voor eigenaren (rol owner, gekoppeld aan een klant)
+			portal := protected.Group("/portal")
+			portal.Use(middleware.RequirePermission(models.PermOwnerPortal))
+			{
+				portal.GET("/businesses", handlers.GetPortalBusinesses)
+				portal.GET("/businesses/:id", handlers.GetPortalBusiness)
+				portal.POST("/businesses/:id/proposals", handlers.CreatePortalProposal)
+				portal.GET("/proposals", handlers.GetPortalProposals)
+				portal.POST("/proposals/:id/withdraw", handlers.WithdrawPortalProposal)
+				portal.GET("/invoices", handlers.GetPortalInvoices)
+				portal.GET("/reviews", handlers.GetPortalReviews)
+				portal.PUT("/reviews/:id/reply", handlers.SetPortalReviewReply)
 			}
 
-			// Student + Admin routes
+			// CRM routes (studenten, teamleiders en admin)
 			crm := protected.Group("/crm")
-			crm.Use(middleware.StudentOrAdmin())
+			crm.Use(middleware.RequirePermission(models.PermCustomersRead))
 			{
-				// Hier komen later communication endpoints
+				crm.GET("/customers", handlers.GetCustomers)
+				crm.GET("/customers/:id", handlers.GetCustomerByID)
+				crm.POST("/customers", middleware.RequirePermission(models.PermCustomersWrite), handlers.CreateCustomer)
+				crm.PUT("/customers/:id", middleware.RequirePermission(models.PermCustomersWrite), handlers.UpdateCustomer)
+
+				crm.GET("/customers/:id/communications", middleware.RequirePermission(models.PermCommunicationsRead), handlers.GetCustomerCommunications)
+				crm.POST("/customers/:id/communications", middleware.RequirePermission(models.PermCommunicationsWrite), handlers.CreateCommunication)
+
+				crm.GET("/commission", handlers.GetCommission)
+
+				// Supervisors: eigen teams en rapportage per team
+				crm.GET("/teams", middleware.RequirePermission(models.PermTeamRead), handlers.GetMyTeams)
+				crm.GET("/reports/teams", middleware.RequirePermission(models.PermTeamRead), handlers.GetTeamReports)
 			}
 		}
 	}
@@ -109,6 +393,7 @@ func main() {
 	fmt.Println("🔒 Production mode - Credentials are secured")
 	fmt.Println("📊 Rate limiting active:")
 	fmt.Println("   - Login: 5 attempts / 15 min")
+	fmt.Println("   - Reviews: 5 / hour")
 	fmt.Println("   - Global: 100 requests / min")
 
 	r.Run(":" + port)
@@ -1,7 +1,7 @@
 'use client';
 
 import { useState, useEffect } from 'react';
-import { getBusinesses, Business, BusinessResponse, createBusiness } from '@/lib/api';
+import { getAllBusinesses, Business, createBusiness } from '@/lib/api';
 import { getProfile, removeToken, User } from '@/lib/api';
 import { useRouter } from 'next/navigation';
 
@@ -37,8 +37,7 @@ export default function AdminPage() {
     const fetchBusinesses = async () => {
         try {
             setLoading(true);
-            const response: BusinessResponse = await getBusinesses();
-            setBusinesses(response.businesses);
+            setBusinesses(await getAllBusinesses());
         } catch (error) {
             console.error('Error fetching businesses:', error);
         } finally {
@@ -1,7 +1,7 @@
 'use client';
 
 import { useState, useEffect } from 'react';
-import { getProfile, getBusinesses, removeToken, User, Business, BusinessResponse } from '@/lib/api';
+import { getProfile, getAllBusinesses, removeToken, User, Business } from '@/lib/api';
 import { useRouter } from 'next/navigation';
 
 export default function StudentDashboard() {
@@ -21,8 +21,7 @@ export default function StudentDashboard() {
             setUser(profile);
 
             // Get businesses for overview
-            const response: BusinessResponse = await getBusinesses();
-            setBusinesses(response.businesses);
+            setBusinesses(await getAllBusinesses());
         } catch (error) {
             console.error('Dashboard error:', error);
             // Redirect to login if auth fails
@@ -1,7 +1,7 @@
 'use client';
 
 import { useState, useEffect } from 'react';
-import { getBusinesses, Business } from '@/lib/api';
+import { getAllBusinesses, Business } from '@/lib/api';
 import dynamic from 'next/dynamic';
 
 // Dynamic import - NO SSR for map!
@@ -26,8 +26,7 @@ export default function HomePage() {
 
     const fetchBusinesses = async () => {
         try {
-            const response = await getBusinesses();
-            setBusinesses(response.businesses || []);
+            setBusinesses(await getAllBusinesses());
         } catch (error) {
             console.error('Error fetching businesses:', error);
         } finally {
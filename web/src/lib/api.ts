@@ -6,12 +6,19 @@ export interface Business {
     name: string;
     category: string;
     sub_category: string;
+    category_name?: string;
+    sub_category_name?: string;
+    language?: string;
+    thumbnail_url?: string;
+    logo_url?: string;
+    photos?: BusinessPhoto[];
     address: string;
     city: string;
     country: string;
     postal_code: string;
     latitude: number;
     longitude: number;
+    geocode_status?: '' | 'ok' | 'geocoded' | 'mismatch' | 'unknown_postcode';
     phone: string;
     website: string;
     email: string;
@@ -19,30 +26,182 @@ export interface Business {
     is_active: boolean;
     created_at: string;
     updated_at: string;
+    travel?: TravelInfo;
+    fuel_station_id?: string;
+    fuel_prices?: FuelPrice[];
+    deals?: Deal[];
+    rating_average: number | null;
+    rating_count: number;
+    listing_tier?: ListingTier;
+}
+
+export type ListingTier = 'basic' | 'featured' | 'premium';
+
+export interface Review {
+    id: number;
+    business_id: number;
+    author_name: string;
+    rating: number;
+    text: string;
+    language: string;
+    visit_date?: string;
+    status: 'pending' | 'approved' | 'rejected' | 'spam';
+    reply?: string;
+    reply_at?: string;
+    created_at: string;
+}
+
+export interface NewReview {
+    rating: number;
+    text: string;
+    language?: string;
+    visit_date?: string;
+    author_name?: string;
+    website?: string; // Honeypot, keep empty (hidden field)
+}
+
+// Submit a review; it is shown after moderation
+export async function submitReview(businessId: number, review: NewReview): Promise<void> {
+    const response = await fetch(`${API_BASE_URL}/businesses/${businessId}/reviews`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        credentials: 'include',
+        body: JSON.stringify(review),
+    });
+
+    if (!response.ok) {
+        const error = await response.json().catch(() => ({}));
+        throw new Error(error.error || 'Failed to submit review');
+    }
+}
+
+export interface Deal {
+    id: number;
+    business_id: number;
+    business?: Business;
+    title: string;
+    description: string;
+    terms: string;
+    starts_at: string;
+    ends_at: string;
+    code_mode: 'none' | 'shared' | 'unique';
+    max_per_visitor: number;
+    max_total: number;
+}
+
+// Redeem a deal; visitorId is a random ID kept in localStorage.
+// Deals with max_per_visitor need a login (401 otherwise).
+export async function redeemDeal(id: number, visitorId: string): Promise<{ code: string; remaining?: number }> {
+    const token = getToken();
+    const response = await fetch(`${API_BASE_URL}/deals/${id}/redeem`, {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json',
+            ...(token ? { 'Authorization': `Bearer ${token}` } : {}),
+        },
+        body: JSON.stringify({ visitor_id: visitorId }),
+    });
+
+    if (!response.ok) {
+        const error = await response.json().catch(() => ({}));
+        throw new Error(error.error || 'Failed to redeem deal');
+    }
+
+    return response.json();
+}
+
+export type FuelType = 'e5' | 'e10' | 'diesel' | 'lpg';
+
+export interface FuelPrice {
+    id: number;
+    business_id: number;
+    fuel_type: FuelType;
+    price: number;
+    observed_at: string;
+}
+
+export interface FuelOffer {
+    business: Business;
+    fuel_type: FuelType;
+    price: number;
+    observed_at: string;
+    distance_km: number;
+    savings_per_liter?: number;
+    savings_per_tank?: number;
+}
+
+// Cheapest stations near a Dutch postcode (or "lat,lng" via near)
+export async function getCheapestFuel(fuel: FuelType, from: string, radiusKm = 25): Promise<FuelOffer[]> {
+    const params = new URLSearchParams({ fuel, from, radius_km: String(radiusKm) });
+    const response = await fetch(`${API_BASE_URL}/fuel-prices/cheapest?${params.toString()}`);
+
+    if (!response.ok) {
+        throw new Error('Failed to fetch fuel prices');
+    }
+
+    const data = await response.json();
+    return data.stations;
+}
+
+// Distance and travel time from ?from= (Dutch postcode or lat,lng)
+export interface TravelInfo {
+    straight_km: number;
+    road_km: number;
+    duration_minutes: number;
+    nearest_border: { name: string; road?: string; distance_km: number };
+}
+
+export interface BusinessPhoto {
+    id: number;
+    kind: 'photo' | 'logo';
+    width: number;
+    height: number;
+    sort_order: number;
+    is_primary: boolean;
+    caption: string;
+    urls: Record<'large' | 'medium' | 'thumb', string>;
 }
 
 export interface BusinessResponse {
     success: boolean;
     count: number;
+    total: number;
     businesses: Business[];
     filters: {
         category?: string;
         city?: string;
         subcategory?: string;
     };
+    pagination: {
+        limit: number;
+        sort: string;
+        tiered: boolean; // Premium listings first
+        total: number;
+        next_cursor: string | null;
+        has_more: boolean;
+    };
 }
 
-// Get all businesses with optional filters
-export async function getBusinesses(filters?: {
+export interface BusinessFilters {
     category?: string;
     city?: string;
     subcategory?: string;
+    from?: string;
+}
+
+// Get one page of businesses (default 50, max 200); pass next_cursor for the next page
+export async function getBusinesses(filters?: BusinessFilters & {
+    limit?: number;
+    cursor?: string;
 }): Promise<BusinessResponse> {
     const params = new URLSearchParams();
 
     if (filters?.category) params.append('category', filters.category);
     if (filters?.city) params.append('city', filters.city);
     if (filters?.subcategory) params.append('subcategory', filters.subcategory);
+    if (filters?.from) params.append('from', filters.from);
+    if (filters?.limit) params.append('limit', String(filters.limit));
+    if (filters?.cursor) params.append('cursor', filters.cursor);
 
     const url = `${API_BASE_URL}/businesses${params.toString() ? `?${params.toString()}` : ''}`;
 
@@ -55,6 +214,83 @@ export async function getBusinesses(filters?: {
     return response.json();
 }
 
+// Get all businesses by following next_cursor (for pages that filter client-side)
+export async function getAllBusinesses(filters?: BusinessFilters): Promise<Business[]> {
+    const businesses: Business[] = [];
+    let cursor: string | undefined;
+
+    do {
+        const page = await getBusinesses({ ...filters, limit: 200, cursor });
+        businesses.push(...(page.businesses || []));
+        cursor = page.pagination.next_cursor ?? undefined;
+    } while (cursor);
+
+    return businesses;
+}
+
+// GeoJSON types for the map endpoints
+export interface MapFeature {
+    type: 'Feature';
+    id: number | string;
+    geometry: { type: 'Point'; coordinates: [number, number] };
+    properties: {
+        cluster?: boolean;
+        count?: number;
+        featured?: number; // Featured/premium businesses in a cluster
+        categories?: Record<string, number>;
+        name?: string;
+        category?: string;
+        listing_tier?: ListingTier;
+        highlight?: boolean;
+        [key: string]: unknown;
+    };
+}
+
+export interface MapFeatureCollection {
+    type: 'FeatureCollection';
+    features: MapFeature[];
+    bbox?: number[];
+}
+
+// Get clustered businesses for the visible map area (bbox = west,south,east,north)
+export async function getMapClusters(bbox: string, zoom: number, category?: string): Promise<MapFeatureCollection> {
+    const params = new URLSearchParams({ bbox, zoom: String(zoom) });
+    if (category) params.append('category', category);
+
+    const response = await fetch(`${API_BASE_URL}/map/clusters?${params.toString()}`);
+
+    if (!response.ok) {
+        throw new Error('Failed to fetch map clusters');
+    }
+
+    return response.json();
+}
+
+export interface Category {
+    id: number;
+    slug: string;
+    parent_id: number | null;
+    icon: string;
+    sort_order: number;
+    is_active: boolean;
+    name?: string;
+    names?: Record<string, string>;
+    children?: Category[];
+}
+
+// Get category tree (names in the requested language)
+export async function getCategories(lang?: string): Promise<Category[]> {
+    const params = lang ? `?lang=${encodeURIComponent(lang)}` : '';
+    const response = await fetch(`${API_BASE_URL}/categories${params}`);
+
+    if (!response.ok) {
+        throw new Error('Failed to fetch categories');
+    }
+
+    const data = await response.json();
+    return data.categories;
+}
+
 // Get single business by ID
 export async function getBusinessById(id: number): Promise<Business> {
     const response = await fetch(`${API_BASE_URL}/businesses/${id}`);
@@ -84,6 +320,47 @@ export async function createBusiness(business: Partial<Business>): Promise<Busin
     return result.business;
 }
 
+// Authenticated request helper for admin endpoints
+async function adminRequest(path: string, method: string, body?: unknown) {
+    const token = getToken();
+    const response = await fetch(`${API_BASE_URL}/admin${path}`, {
+        method,
+        headers: {
+            'Content-Type': 'application/json',
+            ...(token ? { 'Authorization': `Bearer ${token}` } : {}),
+        },
+        body: body ? JSON.stringify(body) : undefined,
+    });
+
+    if (!response.ok) {
+        const error = await response.json().catch(() => ({}));
+        throw new Error(error.error || `Request failed: ${method} ${path}`);
+    }
+
+    return response.json();
+}
+
+// Update business (partial)
+export async function updateBusiness(id: number, changes: Partial<Business>): Promise<Business> {
+    const result = await adminRequest(`/businesses/${id}`, 'PATCH', changes);
+    return result.business;
+}
+
+// Hide business from the public site
+export async function deactivateBusiness(id: number): Promise<void> {
+    await adminRequest(`/businesses/${id}/deactivate`, 'POST');
+}
+
+// Make a deactivated business visible again
+export async function activateBusiness(id: number): Promise<void> {
+    await adminRequest(`/businesses/${id}/activate`, 'POST');
+}
+
+// Permanently delete business (admin only)
+export async function deleteBusiness(id: number): Promise<void> {
+    await adminRequest(`/businesses/${id}`, 'DELETE');
+}
+
 // Auth types
 export interface LoginRequest {
     email: string;
@@ -95,9 +372,10 @@ export interface User {
     email: string;
     first_name: string;
     last_name: string;
-    role: 'admin' | 'student';
+    role: 'admin' | 'student' | 'teamlead' | 'finance' | 'editor' | 'owner';
     student_id?: string;
     university?: string;
+    customer_id?: number; // Owners only
 }
 
 export interface LoginResponse {
@@ -154,6 +432,37 @@ export function removeToken(): void {
     localStorage.removeItem('auth_token');
 }
 
+// Owner portal: proposed edits are applied after admin approval
+export interface BusinessEditProposal {
+    id: number;
+    business_id: number;
+    changes: Record<string, string>; // opening_hours as "mon 09:00-18:00; tue 09:00-18:00"
+    original: Record<string, string>;
+    message: string;
+    status: 'pending' | 'approved' | 'rejected' | 'withdrawn';
+    review_note?: string;
+    created_at: string;
+}
+
+export async function proposeBusinessEdit(businessId: number, changes: Record<string, string>, message = ''): Promise<BusinessEditProposal> {
+    const response = await fetch(`${API_BASE_URL}/portal/businesses/${businessId}/proposals`, {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json',
+            'Authorization': `Bearer ${getToken()}`,
+        },
+        body: JSON.stringify({ changes, message }),
+    });
+
+    if (!response.ok) {
+        const error = await response.json().catch(() => ({}));
+        throw new Error(error.error || 'Failed to submit proposal');
+    }
+
+    const data = await response.json();
+    return data.proposal;
+}
+
 export function getToken(): string | null {
     return localStorage.getItem('auth_token');
 }